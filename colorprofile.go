@@ -0,0 +1,125 @@
+package terminal
+
+import (
+	"os"
+	"strings"
+
+	"github.com/halimath/terminal/sgr"
+)
+
+// DetectColorProfile determines the color profile supported by the environment this process runs in.
+// isTerminal signals whether the output is connected to a terminal. The following rules are applied in
+// order:
+//
+//   - NO_COLOR set to a non-empty value disables colors.
+//   - FORCE_COLOR enables colors even if the output is not a terminal. The values 0 and false disable
+//     colors, 1 and true enable the profile detected from the environment (but at least ANSI), 2
+//     selects ANSI256 and 3 selects truecolor.
+//   - CLICOLOR_FORCE set to a value other than 0 enables colors even if the output is not a terminal.
+//   - CLICOLOR=0 disables colors.
+//   - Known CI environments enable colors even though the output is not a terminal.
+//   - If the output is not a terminal, colors are disabled.
+//   - TERM=dumb disables colors.
+//   - COLORTERM, TERM_PROGRAM and TERM are inspected to determine the supported colors. If none of them
+//     signals a specific profile, ANSI is assumed.
+func DetectColorProfile(isTerminal bool) sgr.ColorProfile {
+	if os.Getenv("NO_COLOR") != "" {
+		return sgr.ProfileNone
+	}
+
+	if v, ok := os.LookupEnv("FORCE_COLOR"); ok {
+		switch strings.ToLower(v) {
+		case "0", "false":
+			return sgr.ProfileNone
+		case "2":
+			return sgr.ProfileANSI256
+		case "3":
+			return sgr.ProfileTrueColor
+		default:
+			return atLeastANSI(detectEnvColorProfile())
+		}
+	}
+
+	if v := os.Getenv("CLICOLOR_FORCE"); v != "" && v != "0" {
+		return atLeastANSI(detectEnvColorProfile())
+	}
+
+	if os.Getenv("CLICOLOR") == "0" {
+		return sgr.ProfileNone
+	}
+
+	if p, ok := detectCIColorProfile(); ok {
+		return p
+	}
+
+	if !isTerminal {
+		return sgr.ProfileNone
+	}
+
+	return detectEnvColorProfile()
+}
+
+func atLeastANSI(p sgr.ColorProfile) sgr.ColorProfile {
+	if p < sgr.ProfileANSI {
+		return sgr.ProfileANSI
+	}
+	return p
+}
+
+// detectCIColorProfile determines the color profile of known CI environments. The returned bool is false
+// if no CI environment has been detected.
+func detectCIColorProfile() (sgr.ColorProfile, bool) {
+	if os.Getenv("GITHUB_ACTIONS") == "true" || os.Getenv("GITEA_ACTIONS") == "true" {
+		return sgr.ProfileTrueColor, true
+	}
+
+	for _, name := range []string{"GITLAB_CI", "BUILDKITE", "CIRCLECI", "TRAVIS", "DRONE", "APPVEYOR"} {
+		if os.Getenv(name) != "" {
+			return sgr.ProfileANSI256, true
+		}
+	}
+
+	if os.Getenv("CI") != "" {
+		return sgr.ProfileANSI, true
+	}
+
+	return sgr.ProfileNone, false
+}
+
+// detectEnvColorProfile determines the color profile from COLORTERM, TERM_PROGRAM and TERM.
+func detectEnvColorProfile() sgr.ColorProfile {
+	term := strings.ToLower(os.Getenv("TERM"))
+	if term == "dumb" {
+		return sgr.ProfileNone
+	}
+
+	switch strings.ToLower(os.Getenv("COLORTERM")) {
+	case "truecolor", "24bit":
+		return sgr.ProfileTrueColor
+	}
+
+	switch os.Getenv("TERM_PROGRAM") {
+	case "iTerm.app", "WezTerm", "vscode", "ghostty", "Hyper", "rio", "WarpTerminal", "Tabby":
+		return sgr.ProfileTrueColor
+	case "Apple_Terminal":
+		return sgr.ProfileANSI256
+	}
+
+	if os.Getenv("WT_SESSION") != "" {
+		// Windows Terminal
+		return sgr.ProfileTrueColor
+	}
+
+	switch {
+	case strings.HasSuffix(term, "-direct"), strings.Contains(term, "truecolor"), strings.Contains(term, "24bit"):
+		return sgr.ProfileTrueColor
+	case strings.HasPrefix(term, "xterm-kitty"), strings.HasPrefix(term, "xterm-ghostty"),
+		strings.HasPrefix(term, "alacritty"), strings.HasPrefix(term, "foot"), strings.HasPrefix(term, "wezterm"),
+		strings.HasPrefix(term, "contour"):
+		return sgr.ProfileTrueColor
+	case strings.HasSuffix(term, "256color"):
+		return sgr.ProfileANSI256
+	}
+
+	return sgr.ProfileANSI
+}
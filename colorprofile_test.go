@@ -0,0 +1,71 @@
+package terminal
+
+import (
+	"os"
+	"testing"
+
+	"github.com/halimath/expect"
+	"github.com/halimath/expect/is"
+	"github.com/halimath/terminal/sgr"
+)
+
+var colorProfileEnvVars = []string{
+	"NO_COLOR", "FORCE_COLOR", "CLICOLOR", "CLICOLOR_FORCE", "TERM", "COLORTERM", "TERM_PROGRAM", "WT_SESSION",
+	"CI", "GITHUB_ACTIONS", "GITEA_ACTIONS", "GITLAB_CI", "BUILDKITE", "CIRCLECI", "TRAVIS", "DRONE", "APPVEYOR",
+}
+
+func TestDetectColorProfile(t *testing.T) {
+	type testCase struct {
+		env        map[string]string
+		isTerminal bool
+		want       sgr.ColorProfile
+	}
+
+	tests := []testCase{
+		{map[string]string{}, false, sgr.ProfileNone},
+		{map[string]string{}, true, sgr.ProfileANSI},
+		{map[string]string{"TERM": "xterm-256color"}, true, sgr.ProfileANSI256},
+		{map[string]string{"TERM": "xterm-256color"}, false, sgr.ProfileNone},
+		{map[string]string{"TERM": "xterm-256color", "COLORTERM": "24bit"}, true, sgr.ProfileTrueColor},
+		{map[string]string{"TERM": "xterm-256color", "COLORTERM": "truecolor"}, true, sgr.ProfileTrueColor},
+		{map[string]string{"TERM": "xterm-kitty"}, true, sgr.ProfileTrueColor},
+		{map[string]string{"TERM": "dumb", "COLORTERM": "truecolor"}, true, sgr.ProfileNone},
+		{map[string]string{"TERM_PROGRAM": "Apple_Terminal"}, true, sgr.ProfileANSI256},
+		{map[string]string{"TERM_PROGRAM": "iTerm.app"}, true, sgr.ProfileTrueColor},
+		{map[string]string{"NO_COLOR": "1", "COLORTERM": "truecolor"}, true, sgr.ProfileNone},
+		{map[string]string{"NO_COLOR": "1", "FORCE_COLOR": "3"}, true, sgr.ProfileNone},
+		{map[string]string{"FORCE_COLOR": "1"}, false, sgr.ProfileANSI},
+		{map[string]string{"FORCE_COLOR": "", "TERM": "xterm-256color"}, false, sgr.ProfileANSI256},
+		{map[string]string{"FORCE_COLOR": "3"}, false, sgr.ProfileTrueColor},
+		{map[string]string{"FORCE_COLOR": "0", "COLORTERM": "truecolor"}, true, sgr.ProfileNone},
+		{map[string]string{"CLICOLOR_FORCE": "1"}, false, sgr.ProfileANSI},
+		{map[string]string{"CLICOLOR_FORCE": "0"}, false, sgr.ProfileNone},
+		{map[string]string{"CLICOLOR": "0", "TERM": "xterm-256color"}, true, sgr.ProfileNone},
+		{map[string]string{"CI": "true"}, false, sgr.ProfileANSI},
+		{map[string]string{"CI": "true", "CLICOLOR": "0"}, false, sgr.ProfileNone},
+		{map[string]string{"CI": "true", "GITHUB_ACTIONS": "true"}, false, sgr.ProfileTrueColor},
+		{map[string]string{"GITLAB_CI": "true"}, false, sgr.ProfileANSI256},
+	}
+
+	for _, test := range tests {
+		for _, name := range colorProfileEnvVars {
+			t.Setenv(name, "")
+			unsetenv(t, name)
+		}
+		for name, value := range test.env {
+			t.Setenv(name, value)
+		}
+
+		expect.WithMessage(t, "%v, isTerminal=%v", test.env, test.isTerminal).
+			That(is.EqualTo(DetectColorProfile(test.isTerminal), test.want))
+	}
+}
+
+// unsetenv unsets the environment variable name for the remainder of t. t.Setenv must have been called for
+// name before to restore the original value when t completes.
+func unsetenv(t *testing.T, name string) {
+	t.Helper()
+	if err := os.Unsetenv(name); err != nil {
+		t.Fatal(err)
+	}
+}
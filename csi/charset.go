@@ -0,0 +1,177 @@
+package csi
+
+import (
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// Commands to designate character sets. A terminal holds up to four character sets G0 to G3 with G0
+	// being used by default. Designating the DEC special graphics set to G0 causes the characters 0x60 to
+	// 0x7e to be displayed as line drawing characters until the ASCII set is designated again.
+	DesignateG0DECSpecialGraphics = ESC + "(0" // Designates DEC special graphics as G0
+	DesignateG0ASCII              = ESC + "(B" // Designates US ASCII as G0
+	DesignateG1DECSpecialGraphics = ESC + ")0" // Designates DEC special graphics as G1
+	DesignateG1ASCII              = ESC + ")B" // Designates US ASCII as G1
+
+	// Commands to switch between the character sets G0 and G1.
+	ShiftOut = "\x0e" // Use G1 for all following characters (SO)
+	ShiftIn  = "\x0f" // Use G0 for all following characters (SI)
+)
+
+// decSpecialGraphics maps Unicode runes to the character displaying them when the DEC special graphics set
+// is active. Heavy, double and rounded box drawing characters are mapped to their light counterparts.
+var decSpecialGraphics = map[rune]byte{
+	'◆': '`', '▒': 'a', '␉': 'b', '␌': 'c', '␍': 'd', '␊': 'e', '°': 'f', '±': 'g',
+	'␤': 'h', '␋': 'i', '┘': 'j', '┐': 'k', '┌': 'l', '└': 'm', '┼': 'n', '⎺': 'o',
+	'⎻': 'p', '─': 'q', '⎼': 'r', '⎽': 's', '├': 't', '┤': 'u', '┴': 'v', '┬': 'w',
+	'│': 'x', '≤': 'y', '≥': 'z', 'π': '{', '≠': '|', '£': '}', '·': '~',
+
+	'━': 'q', '═': 'q', '┃': 'x', '║': 'x',
+	'┏': 'l', '╔': 'l', '╭': 'l',
+	'┓': 'k', '╗': 'k', '╮': 'k',
+	'┗': 'm', '╚': 'm', '╰': 'm',
+	'┛': 'j', '╝': 'j', '╯': 'j',
+	'┣': 't', '╠': 't', '┫': 'u', '╣': 'u',
+	'┳': 'w', '╦': 'w', '┻': 'v', '╩': 'v',
+	'╋': 'n', '╬': 'n',
+}
+
+// TranslateBoxDrawing translates all box drawing characters (and the other characters contained in the DEC
+// special graphics set) in s into DEC special graphics. Every run of such characters is enclosed in
+// DesignateG0DECSpecialGraphics and DesignateG0ASCII. All other characters are kept unchanged.
+//
+// Use this function to output box drawings on terminals lacking UTF-8 support. Use BoxDrawingWriter to
+// translate all output written to a terminal.
+func TranslateBoxDrawing(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+
+	special := false
+	for _, r := range s {
+		c, ok := decSpecialGraphics[r]
+		if ok != special {
+			if ok {
+				b.WriteString(DesignateG0DECSpecialGraphics)
+			} else {
+				b.WriteString(DesignateG0ASCII)
+			}
+			special = ok
+		}
+
+		if ok {
+			b.WriteByte(c)
+		} else {
+			b.WriteRune(r)
+		}
+	}
+
+	if special {
+		b.WriteString(DesignateG0ASCII)
+	}
+
+	return b.String()
+}
+
+// BoxDrawingWriter is an io.Writer that translates box drawing characters contained in the plain text
+// written to it like TranslateBoxDrawing before writing them to an underlying io.Writer. Control functions
+// are passed through unchanged. Characters and control functions split across multiple calls to Write are
+// handled correctly. Every run of translated characters is terminated by DesignateG0ASCII before any
+// control function and at the end of every call to Write.
+type BoxDrawingWriter struct {
+	w       io.Writer
+	scanner controlScanner
+	special bool
+	r       []byte
+	buf     []byte
+}
+
+// NewBoxDrawingWriter creates a new BoxDrawingWriter writing to w.
+func NewBoxDrawingWriter(w io.Writer) *BoxDrawingWriter {
+	return &BoxDrawingWriter{w: w}
+}
+
+// Write writes p to the underlying io.Writer with all box drawing characters translated. It returns len(p)
+// unless an error occured.
+func (b *BoxDrawingWriter) Write(p []byte) (int, error) {
+	b.buf = b.buf[:0]
+
+	for _, c := range p {
+		b.scanner.scan(c, b.emit)
+	}
+
+	if b.special {
+		b.buf = append(b.buf, DesignateG0ASCII...)
+		b.special = false
+	}
+
+	if len(b.buf) > 0 {
+		if _, err := b.w.Write(b.buf); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(p), nil
+}
+
+// Flush writes an incomplete UTF-8 sequence held back while waiting for the next bytes to the underlying
+// io.Writer.
+func (b *BoxDrawingWriter) Flush() error {
+	b.buf = b.buf[:0]
+	b.scanner.flush(b.emit)
+	b.buf = append(b.buf, b.r...)
+	b.r = b.r[:0]
+
+	if len(b.buf) == 0 {
+		return nil
+	}
+
+	_, err := b.w.Write(b.buf)
+	return err
+}
+
+// emit collects text bytes into runes and translates them. Control function bytes are passed through.
+func (b *BoxDrawingWriter) emit(c byte, text bool) {
+	if !text {
+		// A control function interrupts an incomplete UTF-8 sequence which is passed through as is. The
+		// ASCII set is designated before, so control functions never end up within a translated run.
+		b.buf = append(b.buf, b.r...)
+		b.r = b.r[:0]
+		if b.special {
+			b.buf = append(b.buf, DesignateG0ASCII...)
+			b.special = false
+		}
+		b.buf = append(b.buf, c)
+		return
+	}
+
+	b.r = append(b.r, c)
+	if !utf8.FullRune(b.r) {
+		return
+	}
+
+	r, size := utf8.DecodeRune(b.r)
+	t, ok := decSpecialGraphics[r]
+	if ok != b.special {
+		if ok {
+			b.buf = append(b.buf, DesignateG0DECSpecialGraphics...)
+		} else {
+			b.buf = append(b.buf, DesignateG0ASCII...)
+		}
+		b.special = ok
+	}
+
+	if ok {
+		b.buf = append(b.buf, t)
+	} else {
+		b.buf = append(b.buf, b.r[:size]...)
+	}
+
+	// An invalid UTF-8 sequence consumes only its first byte; process the remaining bytes again.
+	rest := b.r[size:]
+	b.r = b.r[:0]
+	for _, c := range append([]byte(nil), rest...) {
+		b.emit(c, true)
+	}
+}
@@ -0,0 +1,66 @@
+package csi
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/halimath/expect"
+	"github.com/halimath/expect/is"
+)
+
+func TestTranslateBoxDrawing(t *testing.T) {
+	tests := map[string]string{
+		"hello":     "hello",
+		"┌─┐":       "\x1b(0lqk\x1b(B",
+		"│ab│":      "\x1b(0x\x1b(Bab\x1b(0x\x1b(B",
+		"╭━╮ 25°":   "\x1b(0lqk\x1b(B 25\x1b(0f\x1b(B",
+		"└─ ä ─┘\n": "\x1b(0mq\x1b(B ä \x1b(0qj\x1b(B\n",
+	}
+
+	for in, want := range tests {
+		expect.WithMessage(t, "input %q", in).
+			That(is.EqualTo(TranslateBoxDrawing(in), want))
+	}
+}
+
+func TestBoxDrawingWriter(t *testing.T) {
+	t.Run("single", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := NewBoxDrawingWriter(&buf)
+
+		in := "\x1b[1m┌─┐\x1b[0m ä\n\x1b]0;│title│\x07└┘"
+		n, err := w.Write([]byte(in))
+		expect.That(t,
+			is.NoError(err),
+			is.EqualTo(n, len(in)),
+			is.NoError(w.Flush()),
+			is.EqualTo(buf.String(), "\x1b[1m\x1b(0lqk\x1b(B\x1b[0m ä\n\x1b]0;│title│\x07\x1b(0mj\x1b(B"),
+		)
+	})
+
+	t.Run("split", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := NewBoxDrawingWriter(&buf)
+
+		for _, c := range []byte("a─°\x1b[31m│\xc2") {
+			w.Write([]byte{c})
+		}
+
+		expect.That(t,
+			is.NoError(w.Flush()),
+			is.EqualTo(buf.String(), "a\x1b(0q\x1b(B\x1b(0f\x1b(B\x1b[31m\x1b(0x\x1b(B\xc2"),
+		)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := NewBoxDrawingWriter(&buf)
+
+		w.Write([]byte("\xe2a─"))
+
+		expect.That(t,
+			is.NoError(w.Flush()),
+			is.EqualTo(buf.String(), "\xe2a\x1b(0q\x1b(B"),
+		)
+	})
+}
@@ -0,0 +1,87 @@
+package csi
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ClipboardSelection identifies one of the selection buffers that can be accessed using OSC 52.
+type ClipboardSelection byte
+
+const (
+	SelectionClipboard ClipboardSelection = 'c' // The system clipboard
+	SelectionPrimary   ClipboardSelection = 'p' // The primary selection
+	SelectionSecondary ClipboardSelection = 'q' // The secondary selection
+	SelectionSelect    ClipboardSelection = 's' // The selection configured by the terminal (often primary)
+
+	// X11 cut buffers
+	SelectionCutBuffer0 ClipboardSelection = '0'
+	SelectionCutBuffer1 ClipboardSelection = '1'
+	SelectionCutBuffer2 ClipboardSelection = '2'
+	SelectionCutBuffer3 ClipboardSelection = '3'
+	SelectionCutBuffer4 ClipboardSelection = '4'
+	SelectionCutBuffer5 ClipboardSelection = '5'
+	SelectionCutBuffer6 ClipboardSelection = '6'
+	SelectionCutBuffer7 ClipboardSelection = '7'
+)
+
+// clipboardSequenceLimit is the maximum length in bytes of an OSC 52 sequence. Many terminals silently drop
+// longer sequences.
+const clipboardSequenceLimit = 100000
+
+// clipboardFraming is the number of bytes an OSC 52 sequence adds to the base64 encoded data.
+const clipboardFraming = len(OSC + "52;c;" + StringTerminator)
+
+// ClipboardSizeLimit defines the maximum number of raw (unencoded) data bytes that can be written to or
+// read from a selection buffer. It is derived from the 100000 bytes limit of an OSC 52 sequence: without
+// the 9 bytes of framing, 99991 bytes remain for the base64 encoded data. Base64 encodes every 3 bytes as 4
+// bytes, so (99991 / 4) * 3 = 74991 bytes of raw data fit into a single sequence.
+const ClipboardSizeLimit = (clipboardSequenceLimit - clipboardFraming) / 4 * 3
+
+// ErrClipboardDataTooLarge is a sentinel error value returned when the data to write to a selection buffer
+// exceeds ClipboardSizeLimit.
+var ErrClipboardDataTooLarge = errors.New("clipboard data too large")
+
+// SetClipboard creates an OSC 52 sequence that sets the content of the selection buffer selection to data.
+// It returns ErrClipboardDataTooLarge if data exceeds ClipboardSizeLimit.
+//
+// Note that most terminals require OSC 52 write access to be enabled by the user.
+func SetClipboard(selection ClipboardSelection, data []byte) (string, error) {
+	if len(data) > ClipboardSizeLimit {
+		return "", fmt.Errorf("%w: %d > %d", ErrClipboardDataTooLarge, len(data), ClipboardSizeLimit)
+	}
+
+	return fmt.Sprintf("%s52;%c;%s%s", OSC, selection, base64.StdEncoding.EncodeToString(data), StringTerminator), nil
+}
+
+// GetClipboard queries the content of the selection buffer selection and returns the decoded data.
+//
+// Note that most terminals require OSC 52 read access to be enabled by the user. Terminals that do not
+// answer the query cause this function to block until some other input is read.
+func GetClipboard(rw io.ReadWriter, selection ClipboardSelection) ([]byte, error) {
+	query := fmt.Sprintf("%s52;%c;?%s", OSC, selection, StringTerminator)
+	responseLimit := base64.StdEncoding.EncodedLen(ClipboardSizeLimit) + 16
+
+	return execStringQuery(rw, query, responseLimit, func(res []byte) (data []byte, err error) {
+		payload, ok := trimOSC(res)
+		if !ok || !bytes.HasPrefix(payload, []byte("52;")) {
+			err = fmt.Errorf("%w: get clipboard: %q", ErrInvalidTerminalResponse, res)
+			return
+		}
+
+		_, encoded, ok := bytes.Cut(payload[3:], []byte{';'})
+		if !ok {
+			err = fmt.Errorf("%w: get clipboard: %q", ErrInvalidTerminalResponse, res)
+			return
+		}
+
+		data, err = base64.StdEncoding.DecodeString(string(encoded))
+		if err != nil {
+			err = fmt.Errorf("%w: get clipboard: %v", ErrInvalidTerminalResponse, err)
+		}
+		return
+	})
+}
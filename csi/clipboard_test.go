@@ -0,0 +1,71 @@
+package csi
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/halimath/expect"
+	"github.com/halimath/expect/is"
+)
+
+func TestSetClipboard(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		got, err := SetClipboard(SelectionClipboard, []byte("hello, world"))
+		expect.That(t,
+			is.NoError(err),
+			is.EqualTo(got, "\x1b]52;c;aGVsbG8sIHdvcmxk\x1b\\"),
+		)
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		got, err := SetClipboard(SelectionPrimary, bytes.Repeat([]byte{'a'}, ClipboardSizeLimit))
+		expect.That(t,
+			is.NoError(err),
+			is.EqualTo(ClipboardSizeLimit, 74991),
+			is.EqualTo(len(got) <= clipboardSequenceLimit, true),
+		)
+	})
+
+	t.Run("tooLarge", func(t *testing.T) {
+		_, err := SetClipboard(SelectionPrimary, bytes.Repeat([]byte{'a'}, ClipboardSizeLimit+1))
+		expect.That(t, is.Error(err, ErrClipboardDataTooLarge))
+	})
+}
+
+func TestGetClipboard(t *testing.T) {
+	t.Run("validResponse", func(t *testing.T) {
+		var rw rw
+		rw.r.WriteString("\x1b]52;c;aGVsbG8sIHdvcmxk\a")
+
+		got, err := GetClipboard(&rw, SelectionClipboard)
+		expect.That(t,
+			is.NoError(err),
+			is.EqualTo(string(got), "hello, world"),
+			is.EqualTo(rw.w.String(), "\x1b]52;c;?\x1b\\"),
+		)
+	})
+
+	t.Run("invalidResponse", func(t *testing.T) {
+		var rw rw
+		rw.r.WriteString("\x1b]52;c;!!!\x1b\\")
+
+		_, err := GetClipboard(&rw, SelectionClipboard)
+		expect.That(t, is.Error(err, ErrInvalidTerminalResponse))
+	})
+}
+
+func TestTmuxPassthrough(t *testing.T) {
+	expect.That(t, is.EqualTo(TmuxPassthrough("\x1b]52;c;?\x1b\\"), "\x1bPtmux;\x1b\x1b]52;c;?\x1b\x1b\\\x1b\\"))
+}
+
+func TestWithTmuxPassthrough(t *testing.T) {
+	var rw rw
+	rw.r.WriteString("\x1b]52;c;YQ==\x1b\\")
+
+	got, err := GetClipboard(WithTmuxPassthrough(&rw), SelectionClipboard)
+	expect.That(t,
+		is.NoError(err),
+		is.EqualTo(string(got), "a"),
+		is.EqualTo(rw.w.String(), "\x1bPtmux;\x1b\x1b]52;c;?\x1b\x1b\\\x1b\\"),
+	)
+}
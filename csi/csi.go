@@ -29,6 +29,8 @@ const (
 	CSI              = ESC + "["  // Control Sequence Introducer (0x9b)
 	StringTerminator = ESC + "\\" // String terminator sequence (0x9c)- used to terminate some sequences
 	OSC              = ESC + "]"  // Operating System Command (0x9d)
+	DCS              = ESC + "P"  // Device Control String (0x90)
+	APC              = ESC + "_"  // Application Program Command (0x9f)
 
 	ResetTerminal = ESC + "c" // Reset all terminal attributes to their default
 
@@ -57,9 +59,16 @@ const (
 	ClearUntilNewline = CSI + "K"  // Clear from cursor to newline
 )
 
-// SetWindowTitle creates a control sequence to set the window's title to title.
-func SetWindowTitle(title string) string {
-	return fmt.Sprintf("%s2;%s%s", OSC, title, StringTerminator)
+// stripControlCharacters removes all C0 and C1 control characters as well as DEL from s. Use this function
+// to sanitize untrusted payloads of sequences as control characters may terminate the sequence and inject
+// other sequences.
+func stripControlCharacters(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || (r >= 0x7f && r <= 0x9f) {
+			return -1
+		}
+		return r
+	}, s)
 }
 
 const queryBackgroundColor = OSC + "11;?" + StringTerminator
@@ -110,6 +119,27 @@ var ErrInvalidTerminalResponse = errors.New("invalid terminal response")
 // queryHandler is used to define functions that handle terminal query responses.
 type queryHandler[T any] func([]byte) (T, error)
 
+// Query writes query to rw and reads up to responseLimit bytes with a single read. The actual bytes read
+// are passed to handler to produce a response. Query is exposed to allow other packages to implement
+// queries the same way this package does.
+func Query[T any](rw io.ReadWriter, query string, responseLimit int, handler func([]byte) (T, error)) (T, error) {
+	return execQuery(rw, query, responseLimit, handler)
+}
+
+// QueryString works like Query but reads until a complete string response (such as an OSC, DCS or APC
+// sequence) terminated by either ST or BEL has been received or responseLimit bytes have been read.
+func QueryString[T any](rw io.ReadWriter, query string, responseLimit int, handler func([]byte) (T, error)) (T, error) {
+	return execStringQuery(rw, query, responseLimit, handler)
+}
+
+// QueryGuarded works like Query but sends a primary device attributes query right after query. As all
+// terminals answer that query, QueryGuarded does not block when the terminal ignores query. The bytes
+// received before the device attributes response are passed to handler; an empty slice signals that the
+// terminal did not answer query.
+func QueryGuarded[T any](rw io.ReadWriter, query string, responseLimit int, handler func([]byte) (T, error)) (T, error) {
+	return execGuardedQuery(rw, query, responseLimit, handler)
+}
+
 // execQuery writes query to t and reads up to responseLimit bytes. The actual bytes read are passed to
 // handler to produce a response.
 func execQuery[T any](rw io.ReadWriter, query string, responseLimit int, handler queryHandler[T]) (result T, err error) {
@@ -128,3 +158,123 @@ func execQuery[T any](rw io.ReadWriter, query string, responseLimit int, handler
 
 	return handler(buf[:n])
 }
+
+// execStringQuery works like execQuery but reads until a complete string response terminated by either ST
+// or BEL has been received or responseLimit bytes have been read. Use it for queries with responses too
+// large to be delivered with a single read.
+func execStringQuery[T any](rw io.ReadWriter, query string, responseLimit int, handler queryHandler[T]) (result T, err error) {
+	return execQueryUntil(rw, query, responseLimit, isStringTerminated, handler)
+}
+
+// execQueryUntil works like execQuery but continues reading until complete returns true for the bytes read
+// so far or responseLimit bytes have been read.
+func execQueryUntil[T any](rw io.ReadWriter, query string, responseLimit int, complete func([]byte) bool, handler queryHandler[T]) (result T, err error) {
+	_, err = rw.Write([]byte(query))
+	if err != nil {
+		return
+	}
+
+	buf := make([]byte, responseLimit)
+
+	var n, l int
+	for n < len(buf) {
+		l, err = rw.Read(buf[n:])
+		n += l
+		if err != nil {
+			return
+		}
+
+		if complete(buf[:n]) || l == 0 {
+			break
+		}
+	}
+
+	return handler(buf[:n])
+}
+
+const queryPrimaryDeviceAttributes = CSI + "c"
+
+// execGuardedQuery implements QueryGuarded.
+func execGuardedQuery[T any](rw io.ReadWriter, query string, responseLimit int, handler queryHandler[T]) (result T, err error) {
+	return execQueryUntil(rw, query+queryPrimaryDeviceAttributes, responseLimit, isDeviceAttributesResponse, func(res []byte) (T, error) {
+		idx := bytes.LastIndex(res, []byte(CSI+"?"))
+		if idx < 0 || !isDeviceAttributesResponse(res) {
+			var zero T
+			return zero, fmt.Errorf("%w: missing device attributes: %q", ErrInvalidTerminalResponse, res)
+		}
+
+		return handler(res[:idx])
+	})
+}
+
+// isDeviceAttributesResponse returns whether b ends with a primary device attributes response.
+func isDeviceAttributesResponse(b []byte) bool {
+	idx := bytes.LastIndex(b, []byte(CSI+"?"))
+	return idx >= 0 && len(b) > idx+3 && b[len(b)-1] == 'c'
+}
+
+// parseCSIResponse parses res as a control sequence of the form CSI <prefix> Ps ; ... ; Ps <final> and
+// returns the numeric parameters. final may contain intermediate bytes preceding the final byte. Empty
+// parameters are reported as 0. Negative parameters (such as a window position on a secondary monitor) are
+// accepted; callers must validate the range of the values. It returns false, if res does not match the
+// expected form.
+func parseCSIResponse(res []byte, prefix string, final string) ([]int, bool) {
+	if !bytes.HasPrefix(res, []byte(CSI+prefix)) || len(res) < len(CSI+prefix)+len(final) || !bytes.HasSuffix(res, []byte(final)) {
+		return nil, false
+	}
+
+	s := string(res[len(CSI)+len(prefix) : len(res)-len(final)])
+	if s == "" {
+		return nil, true
+	}
+
+	parts := strings.Split(s, ";")
+	params := make([]int, len(parts))
+
+	for i, p := range parts {
+		if p == "" {
+			continue
+		}
+
+		v, err := strconv.Atoi(p)
+		if err != nil {
+			return nil, false
+		}
+		params[i] = v
+	}
+
+	return params, true
+}
+
+// containsInt returns whether v is contained in s.
+func containsInt(s []int, v int) bool {
+	for _, e := range s {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
+
+// isStringTerminated returns whether b ends with either ST or BEL.
+func isStringTerminated(b []byte) bool {
+	return bytes.HasSuffix(b, []byte(StringTerminator)) || bytes.HasSuffix(b, []byte{'\a'})
+}
+
+// trimOSC removes the OSC prefix and the terminating ST or BEL from res. It returns false, if res is not
+// an OSC sequence.
+func trimOSC(res []byte) ([]byte, bool) {
+	if !bytes.HasPrefix(res, []byte(OSC)) {
+		return nil, false
+	}
+
+	if bytes.HasSuffix(res, []byte(StringTerminator)) {
+		return res[len(OSC) : len(res)-len(StringTerminator)], true
+	}
+
+	if bytes.HasSuffix(res, []byte{'\a'}) {
+		return res[len(OSC) : len(res)-1], true
+	}
+
+	return nil, false
+}
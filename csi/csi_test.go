@@ -7,10 +7,6 @@ import (
 	"github.com/halimath/expect/is"
 )
 
-func TestSetWindowTitle(t *testing.T) {
-	expect.That(t, is.EqualTo(SetWindowTitle("hello, world"), "\x1b]2;hello, world\x1b\\"))
-}
-
 func TestGetBackgroundColor(t *testing.T) {
 	t.Run("validResponse", func(t *testing.T) {
 		var rw rw
@@ -2,6 +2,7 @@ package csi
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"strconv"
@@ -14,6 +15,17 @@ const (
 	CursorHide    = CSI + "?25l" // Hide cursor
 	CursorShow    = CSI + "?25h" // Show cursor
 
+	// Commands to save and restore the cursor using the DEC sequences (DECSC/DECRC). In contrast to
+	// CursorSave and CursorRestore (which use the SCO sequences) these also save and restore the character
+	// attributes (SGR), the character sets and the origin mode.
+	CursorSaveDEC    = ESC + "7" // Saves the cursor position and attributes
+	CursorRestoreDEC = ESC + "8" // Restores a previously saved cursor position and attributes
+
+	// Commands to manage tab stops
+	TabStopSet      = ESC + "H"  // Sets a tab stop at the current cursor column (HTS)
+	TabStopClear    = CSI + "0g" // Clears the tab stop at the current cursor column (TBC)
+	TabStopClearAll = CSI + "3g" // Clears all tab stops (TBC)
+
 	// Commands to set cursor style
 	CursorBlinkingBlock     = CSI + "\x31 q" // Change the cursor style to blinking block
 	CursorSteadyBlock       = CSI + "\x32 q" // Change the cursor style to steady block
@@ -21,8 +33,15 @@ const (
 	CursorSteadyUnderline   = CSI + "\x34 q" // Change the cursor style to steady underline
 	CursorBlinkingBar       = CSI + "\x35 q" // Change the cursor style to blinking bar
 	CursorSteadyBar         = CSI + "\x36 q" // Change the cursor style to steady bar
+	CursorResetStyle        = CSI + "\x30 q" // Reset the cursor style to the terminal's default
 )
 
+// SetCursorStyle formats a CSI to set the cursor style (DECSCUSR) to style. Valid values are 0 (default) to
+// 6; see the CursorBlinkingBlock, ..., CursorSteadyBar constants for the meaning of each value.
+func SetCursorStyle(style int) string {
+	return fmt.Sprintf("%s%d q", CSI, style)
+}
+
 // MoveCursorUp formats a CSI to move the cursor up by n rows.
 func MoveCursorUp(n int) string {
 	return fmt.Sprintf("%s%dA", CSI, n)
@@ -43,6 +62,76 @@ func MoveCursorBackward(n int) string {
 	return fmt.Sprintf("%s%dD", CSI, n)
 }
 
+// CursorNextLine formats a CSI to move the cursor to the first column of the line n rows down (CNL).
+func CursorNextLine(n int) string {
+	return fmt.Sprintf("%s%dE", CSI, n)
+}
+
+// CursorPrevLine formats a CSI to move the cursor to the first column of the line n rows up (CPL).
+func CursorPrevLine(n int) string {
+	return fmt.Sprintf("%s%dF", CSI, n)
+}
+
+// CursorHorizontalAbsolute formats a CSI to move the cursor to column x of the current row (CHA). The
+// column is 1 based.
+func CursorHorizontalAbsolute(x int) string {
+	return fmt.Sprintf("%s%dG", CSI, x)
+}
+
+// CursorVerticalAbsolute formats a CSI to move the cursor to row y keeping the current column (VPA). The
+// row is 1 based.
+func CursorVerticalAbsolute(y int) string {
+	return fmt.Sprintf("%s%dd", CSI, y)
+}
+
+// CursorTabForward formats a CSI to move the cursor forward by n tab stops (CHT).
+func CursorTabForward(n int) string {
+	return fmt.Sprintf("%s%dI", CSI, n)
+}
+
+// CursorTabBackward formats a CSI to move the cursor backward by n tab stops (CBT).
+func CursorTabBackward(n int) string {
+	return fmt.Sprintf("%s%dZ", CSI, n)
+}
+
+// ErrWidthProbeFailed is returned from ProbeWidth if the width could not be determined because the probed
+// string did not fit on the current line.
+var ErrWidthProbeFailed = errors.New("failed to probe width")
+
+// ProbeWidth determines the number of cells the terminal uses to display s. This allows detecting how a
+// terminal handles grapheme clusters, East Asian wide characters and emoji. ProbeWidth queries the cursor
+// position, writes s, queries the cursor position again and returns the difference of the columns. After
+// probing, s is erased and the cursor is moved back to its original column.
+//
+// s must not contain line breaks or other control characters and must fit on the current line. If s causes
+// the cursor to move to another line, ErrWidthProbeFailed is returned. Make sure the terminal is in raw mode
+// to receive the responses.
+func ProbeWidth(rw io.ReadWriter, s string) (int, error) {
+	x1, y1, err := GetCursorPosition(rw)
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := io.WriteString(rw, s); err != nil {
+		return 0, err
+	}
+
+	x2, y2, err := GetCursorPosition(rw)
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := io.WriteString(rw, CursorHorizontalAbsolute(x1)+ClearUntilNewline); err != nil {
+		return 0, err
+	}
+
+	if y1 != y2 || x2 < x1 {
+		return 0, fmt.Errorf("%w: %q", ErrWidthProbeFailed, s)
+	}
+
+	return x2 - x1, nil
+}
+
 // SetCursorPosition formats a CSI to position the cursor at (x,y).
 //
 // According to ANSI terminal specs both coordinates are 1 based. This function adheres to that spec.
@@ -2,6 +2,7 @@ package csi
 
 import (
 	"bytes"
+	"io"
 	"testing"
 
 	"github.com/halimath/expect"
@@ -28,6 +29,37 @@ func TestSetCursorPosition(t *testing.T) {
 	expect.That(t, is.EqualTo(SetCursorPosition(2, 3), "\x1b[3;2H"))
 }
 
+func TestCursorNextLine(t *testing.T) {
+	expect.That(t, is.EqualTo(CursorNextLine(2), "\x1b[2E"))
+}
+
+func TestCursorPrevLine(t *testing.T) {
+	expect.That(t, is.EqualTo(CursorPrevLine(2), "\x1b[2F"))
+}
+
+func TestCursorHorizontalAbsolute(t *testing.T) {
+	expect.That(t, is.EqualTo(CursorHorizontalAbsolute(1), "\x1b[1G"))
+}
+
+func TestCursorVerticalAbsolute(t *testing.T) {
+	expect.That(t, is.EqualTo(CursorVerticalAbsolute(4), "\x1b[4d"))
+}
+
+func TestCursorTabForward(t *testing.T) {
+	expect.That(t, is.EqualTo(CursorTabForward(2), "\x1b[2I"))
+}
+
+func TestCursorTabBackward(t *testing.T) {
+	expect.That(t, is.EqualTo(CursorTabBackward(2), "\x1b[2Z"))
+}
+
+func TestSetCursorStyle(t *testing.T) {
+	expect.That(t,
+		is.EqualTo(SetCursorStyle(0), CursorResetStyle),
+		is.EqualTo(SetCursorStyle(5), CursorBlinkingBar),
+	)
+}
+
 func TestGetCursorPosition(t *testing.T) {
 	t.Run("validResponse", func(t *testing.T) {
 		var rw rw
@@ -61,6 +93,46 @@ func TestGetCursorPosition(t *testing.T) {
 	})
 }
 
+func TestProbeWidth(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		rw := queueRW{responses: []string{"\x1b[3;5R", "\x1b[3;7R"}}
+
+		w, err := ProbeWidth(&rw, "\U0001f469\u200d\U0001f4bb")
+		expect.That(t,
+			is.NoError(err),
+			is.EqualTo(w, 2),
+			is.EqualTo(rw.w.String(), "\x1b[6n\U0001f469\u200d\U0001f4bb\x1b[6n\x1b[5G\x1b[K"),
+		)
+	})
+
+	t.Run("lineWrapped", func(t *testing.T) {
+		rw := queueRW{responses: []string{"\x1b[3;79R", "\x1b[4;2R"}}
+
+		_, err := ProbeWidth(&rw, "abc")
+		expect.That(t, is.Error(err, ErrWidthProbeFailed))
+	})
+}
+
+// queueRW is a fake terminal that returns a single response for each call to Read.
+type queueRW struct {
+	responses []string
+	w         bytes.Buffer
+}
+
+func (rw *queueRW) Read(p []byte) (int, error) {
+	if len(rw.responses) == 0 {
+		return 0, io.EOF
+	}
+
+	n := copy(p, rw.responses[0])
+	rw.responses = rw.responses[1:]
+	return n, nil
+}
+
+func (rw *queueRW) Write(p []byte) (int, error) {
+	return rw.w.Write(p)
+}
+
 type rw struct {
 	r bytes.Buffer
 	w bytes.Buffer
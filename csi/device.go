@@ -0,0 +1,53 @@
+package csi
+
+import (
+	"fmt"
+	"io"
+)
+
+// Features reported by a terminal as part of the primary device attributes (DA1).
+const (
+	DeviceAttribute132Columns          = 1
+	DeviceAttributePrinter             = 2
+	DeviceAttributeReGIS               = 3
+	DeviceAttributeSixel               = 4
+	DeviceAttributeSelectiveErase      = 6
+	DeviceAttributeUserDefinedKeys     = 8
+	DeviceAttributeNationalCharsets    = 9
+	DeviceAttributeTechnicalCharacters = 15
+	DeviceAttributeLocatorPort         = 16
+	DeviceAttributeStateInterrogation  = 17
+	DeviceAttributeUserWindows         = 18
+	DeviceAttributeHorizontalScrolling = 21
+	DeviceAttributeANSIColor           = 22
+	DeviceAttributeRectangularEditing  = 28
+	DeviceAttributeANSITextLocator     = 29
+)
+
+// DeviceAttributes contains the primary device attributes reported by a terminal.
+type DeviceAttributes struct {
+	// Class is the terminal's conformance level, i.e. 62 for a VT220 or 64 for a VT420 compatible terminal.
+	Class int
+	// Features contains the features supported by the terminal. See the DeviceAttribute... constants.
+	Features []int
+}
+
+// Has returns whether d reports feature to be supported.
+func (d DeviceAttributes) Has(feature int) bool {
+	return containsInt(d.Features, feature)
+}
+
+// GetDeviceAttributes queries the primary device attributes (DA1). All terminals answer this query.
+func GetDeviceAttributes(rw io.ReadWriter) (DeviceAttributes, error) {
+	return execQueryUntil(rw, queryPrimaryDeviceAttributes, 128, isDeviceAttributesResponse, func(res []byte) (d DeviceAttributes, err error) {
+		params, ok := parseCSIResponse(res, "?", "c")
+		if !ok || len(params) == 0 {
+			err = fmt.Errorf("%w: get device attributes: %q", ErrInvalidTerminalResponse, res)
+			return
+		}
+
+		d.Class = params[0]
+		d.Features = params[1:]
+		return
+	})
+}
@@ -0,0 +1,33 @@
+package csi
+
+import (
+	"testing"
+
+	"github.com/halimath/expect"
+	"github.com/halimath/expect/is"
+)
+
+func TestGetDeviceAttributes(t *testing.T) {
+	t.Run("validResponse", func(t *testing.T) {
+		var rw rw
+		rw.r.WriteString("\x1b[?64;1;4;22;28c")
+
+		got, err := GetDeviceAttributes(&rw)
+		expect.That(t,
+			is.NoError(err),
+			is.EqualTo(got.Class, 64),
+			is.EqualTo(got.Has(DeviceAttributeSixel), true),
+			is.EqualTo(got.Has(DeviceAttributeRectangularEditing), true),
+			is.EqualTo(got.Has(DeviceAttributeReGIS), false),
+			is.EqualTo(rw.w.String(), "\x1b[c"),
+		)
+	})
+
+	t.Run("invalidResponse", func(t *testing.T) {
+		var rw rw
+		rw.r.WriteString("\x1b[?x;1c")
+
+		_, err := GetDeviceAttributes(&rw)
+		expect.That(t, is.Error(err, ErrInvalidTerminalResponse))
+	})
+}
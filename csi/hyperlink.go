@@ -0,0 +1,64 @@
+package csi
+
+import (
+	"fmt"
+	"strings"
+)
+
+// HyperlinkClose closes a hyperlink previously opened with HyperlinkOpen.
+const HyperlinkClose = OSC + "8;;" + StringTerminator
+
+// HyperlinkOpen creates an OSC 8 sequence that starts a hyperlink pointing to url. All text written after
+// the sequence up to HyperlinkClose is rendered as a clickable link by supporting terminals. id is optional
+// and may be empty; terminals use it to highlight links that are split over multiple lines (or otherwise
+// interrupted) as a single link.
+//
+// Characters not allowed in url are percent-encoded. See
+// https://gist.github.com/egmontkob/eb114294efbcd5adb1944c9f3cb5feda for the specification.
+func HyperlinkOpen(url, id string) string {
+	var params string
+	if id != "" {
+		params = "id=" + escapeHyperlinkParam(id)
+	}
+
+	return fmt.Sprintf("%s8;%s;%s%s", OSC, params, escapeHyperlinkURI(url), StringTerminator)
+}
+
+// Hyperlink creates a string that renders text as a hyperlink pointing to url. See HyperlinkOpen for a
+// description of id.
+func Hyperlink(url, text, id string) string {
+	return HyperlinkOpen(url, id) + text + HyperlinkClose
+}
+
+// escapeHyperlinkURI percent-encodes all bytes in uri that are outside the printable ASCII range 32-126 as
+// required by the OSC 8 specification.
+func escapeHyperlinkURI(uri string) string {
+	return percentEncode(uri, func(b byte) bool {
+		return b < 0x20 || b > 0x7e
+	})
+}
+
+// escapeHyperlinkParam percent-encodes all bytes in p that are not printable ASCII or that would terminate
+// the parameter or parameter list (':' and ';').
+func escapeHyperlinkParam(p string) string {
+	return percentEncode(p, func(b byte) bool {
+		return b < 0x20 || b > 0x7e || b == ':' || b == ';' || b == '%'
+	})
+}
+
+// percentEncode replaces every byte b in s for which needsEscape returns true with its percent-encoded
+// form %XX.
+func percentEncode(s string, needsEscape func(b byte) bool) string {
+	var b strings.Builder
+	b.Grow(len(s))
+
+	for i := 0; i < len(s); i++ {
+		if needsEscape(s[i]) {
+			fmt.Fprintf(&b, "%%%02X", s[i])
+			continue
+		}
+		b.WriteByte(s[i])
+	}
+
+	return b.String()
+}
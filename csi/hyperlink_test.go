@@ -0,0 +1,22 @@
+package csi
+
+import (
+	"testing"
+
+	"github.com/halimath/expect"
+	"github.com/halimath/expect/is"
+)
+
+func TestHyperlinkOpen(t *testing.T) {
+	expect.That(t,
+		is.EqualTo(HyperlinkOpen("https://example.com", ""), "\x1b]8;;https://example.com\x1b\\"),
+		is.EqualTo(HyperlinkOpen("https://example.com", "a:b"), "\x1b]8;id=a%3Ab;https://example.com\x1b\\"),
+		is.EqualTo(HyperlinkOpen("file:///tmp/föö\x1b", ""), "\x1b]8;;file:///tmp/f%C3%B6%C3%B6%1B\x1b\\"),
+	)
+}
+
+func TestHyperlink(t *testing.T) {
+	expect.That(t,
+		is.EqualTo(Hyperlink("https://example.com", "example", "1"), "\x1b]8;id=1;https://example.com\x1b\\example\x1b]8;;\x1b\\"),
+	)
+}
@@ -0,0 +1,20 @@
+package csi
+
+const (
+	// Commands to change the size of the characters on the line containing the cursor. Double width lines
+	// can hold only half the number of characters. Double height lines must be sent twice: once with the
+	// top and once with the bottom half on two consecutive lines.
+	DoubleHeightLineTop    = ESC + "#3" // Top half of a double height, double width line (DECDHL)
+	DoubleHeightLineBottom = ESC + "#4" // Bottom half of a double height, double width line (DECDHL)
+	SingleWidthLine        = ESC + "#5" // Single height, single width line (DECSWL)
+	DoubleWidthLine        = ESC + "#6" // Single height, double width line (DECDWL)
+
+	// ScreenAlignmentTest fills the whole screen with the character E (DECALN).
+	ScreenAlignmentTest = ESC + "#8"
+)
+
+// Banner creates a string that renders s as a large heading using double height lines. The heading
+// occupies two lines which are both terminated with a line break. s should not contain any line breaks.
+func Banner(s string) string {
+	return DoubleHeightLineTop + s + "\r\n" + DoubleHeightLineBottom + s + "\r\n"
+}
@@ -0,0 +1,12 @@
+package csi
+
+import (
+	"testing"
+
+	"github.com/halimath/expect"
+	"github.com/halimath/expect/is"
+)
+
+func TestBanner(t *testing.T) {
+	expect.That(t, is.EqualTo(Banner("hello"), "\x1b#3hello\r\n\x1b#4hello\r\n"))
+}
@@ -0,0 +1,63 @@
+package csi
+
+import (
+	"fmt"
+	"io"
+)
+
+const (
+	// Synchronized output mode (2026). While in synchronized mode, the terminal keeps rendering the last
+	// frame and applies all output received in between once synchronized mode ends. Wrap full screen
+	// redraws in these sequences to prevent flickering.
+	BeginSynchronizedUpdate = CSI + "?2026h"
+	EndSynchronizedUpdate   = CSI + "?2026l"
+
+	// Grapheme clustering mode (2027). While enabled, the terminal segments output into grapheme clusters
+	// (see Unicode Standard Annex #29) and uses the width of the whole cluster when advancing the cursor,
+	// i.e. an emoji ZWJ sequence occupies two cells rather than the sum of its runes' widths.
+	EnableGraphemeClustering  = CSI + "?2027h"
+	DisableGraphemeClustering = CSI + "?2027l"
+)
+
+// Numbers of private modes that can be passed to GetPrivateModeState.
+const (
+	SynchronizedOutputMode = 2026
+	GraphemeClusteringMode = 2027
+)
+
+// ModeState defines the state of a terminal mode as reported by DECRQM.
+type ModeState int
+
+const (
+	ModeNotRecognized    ModeState = 0 // The mode is not known to the terminal
+	ModeSet              ModeState = 1 // The mode is currently enabled
+	ModeReset            ModeState = 2 // The mode is currently disabled
+	ModePermanentlySet   ModeState = 3 // The mode is enabled and can not be changed
+	ModePermanentlyReset ModeState = 4 // The mode is disabled and can not be changed
+)
+
+// IsSupported returns whether the mode is known to the terminal and can be enabled.
+func (s ModeState) IsSupported() bool {
+	return s == ModeSet || s == ModeReset || s == ModePermanentlySet
+}
+
+// GetPrivateModeState queries the state of the private (DEC) mode mode using DECRQM.
+//
+// As not all terminals support DECRQM, the query is guarded (see QueryGuarded). If the terminal does not
+// answer the DECRQM query, ModeNotRecognized is returned.
+func GetPrivateModeState(rw io.ReadWriter, mode int) (ModeState, error) {
+	query := fmt.Sprintf("%s?%d$p", CSI, mode)
+
+	return execGuardedQuery(rw, query, 128, func(res []byte) (ModeState, error) {
+		if len(res) == 0 {
+			return ModeNotRecognized, nil
+		}
+
+		params, ok := parseCSIResponse(res, "?", "$y")
+		if !ok || len(params) != 2 || params[0] != mode || params[1] < 0 || params[1] > int(ModePermanentlyReset) {
+			return ModeNotRecognized, fmt.Errorf("%w: get mode state: %q", ErrInvalidTerminalResponse, res)
+		}
+
+		return ModeState(params[1]), nil
+	})
+}
@@ -0,0 +1,53 @@
+package csi
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/halimath/expect"
+	"github.com/halimath/expect/is"
+)
+
+func TestGetPrivateModeState(t *testing.T) {
+	t.Run("supported", func(t *testing.T) {
+		var rw rw
+		rw.r.WriteString("\x1b[?2026;2$y\x1b[?62;4;22c")
+
+		got, err := GetPrivateModeState(&rw, SynchronizedOutputMode)
+		expect.That(t,
+			is.NoError(err),
+			is.EqualTo(got, ModeReset),
+			is.EqualTo(got.IsSupported(), true),
+			is.EqualTo(rw.w.String(), "\x1b[?2026$p\x1b[c"),
+		)
+	})
+
+	t.Run("notAnswered", func(t *testing.T) {
+		var rw rw
+		rw.r.WriteString("\x1b[?1;2c")
+
+		got, err := GetPrivateModeState(&rw, SynchronizedOutputMode)
+		expect.That(t,
+			is.NoError(err),
+			is.EqualTo(got, ModeNotRecognized),
+			is.EqualTo(got.IsSupported(), false),
+		)
+	})
+
+	t.Run("invalidResponse", func(t *testing.T) {
+		var rw rw
+		rw.r.WriteString("\x1b[?1049;2$y\x1b[?1;2c")
+
+		_, err := GetPrivateModeState(&rw, SynchronizedOutputMode)
+		expect.That(t, is.Error(err, ErrInvalidTerminalResponse))
+	})
+}
+
+func TestPrivateModeSequences(t *testing.T) {
+	expect.That(t,
+		is.EqualTo(BeginSynchronizedUpdate, fmt.Sprintf("\x1b[?%dh", SynchronizedOutputMode)),
+		is.EqualTo(EndSynchronizedUpdate, fmt.Sprintf("\x1b[?%dl", SynchronizedOutputMode)),
+		is.EqualTo(EnableGraphemeClustering, fmt.Sprintf("\x1b[?%dh", GraphemeClusteringMode)),
+		is.EqualTo(DisableGraphemeClustering, fmt.Sprintf("\x1b[?%dl", GraphemeClusteringMode)),
+	)
+}
@@ -0,0 +1,88 @@
+package csi
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// Notify creates an OSC 9 sequence that posts a desktop notification showing message. This sequence is
+// supported by iTerm2, Windows Terminal, WezTerm and others. Control characters are removed from message.
+func Notify(message string) string {
+	return fmt.Sprintf("%s9;%s%s", OSC, stripControlCharacters(message), StringTerminator)
+}
+
+// NotifyDesktop creates an OSC 777 sequence that posts a desktop notification with the given title and
+// body. This sequence is supported by urxvt, foot, ghostty, VTE based terminals and others. Control
+// characters are removed from both title and body. As the title must not contain a semicolon, any
+// semicolon in title is replaced with a comma.
+func NotifyDesktop(title, body string) string {
+	title = strings.ReplaceAll(stripControlCharacters(title), ";", ",")
+	return fmt.Sprintf("%s777;notify;%s;%s%s", OSC, title, stripControlCharacters(body), StringTerminator)
+}
+
+// KittyNotification creates OSC 99 sequences that post a desktop notification with the given title and
+// body using the kitty notification protocol. id identifies the notification and may be empty; it is
+// used by the terminal to report back activation of the notification. Both title and body are transmitted
+// base64 encoded, so they may contain arbitrary characters.
+//
+// See https://sw.kovidgoyal.net/kitty/desktop-notifications/ for the protocol.
+func KittyNotification(id, title, body string) string {
+	var meta string
+	if id = sanitizeKittyNotificationID(id); id != "" {
+		meta = "i=" + id + ":"
+	}
+
+	if body == "" {
+		return kittyNotificationChunk(meta+"d=1:p=title", title)
+	}
+
+	return kittyNotificationChunk(meta+"d=0:p=title", title) + kittyNotificationChunk(meta+"d=1:p=body", body)
+}
+
+func kittyNotificationChunk(meta, payload string) string {
+	return fmt.Sprintf("%s99;%s:e=1;%s%s", OSC, meta, base64.StdEncoding.EncodeToString([]byte(payload)), StringTerminator)
+}
+
+// sanitizeKittyNotificationID removes all characters from id that are not allowed in a notification
+// identifier.
+func sanitizeKittyNotificationID(id string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || strings.ContainsRune("-_+.", r) {
+			return r
+		}
+		return -1
+	}, id)
+}
+
+// ProgressState defines the state of the progress indicator shown by a terminal in its tab or in the task
+// bar.
+type ProgressState int
+
+const (
+	ProgressHidden        ProgressState = 0 // Hide the progress indicator
+	ProgressNormal        ProgressState = 1 // Show a regular progress indicator
+	ProgressError         ProgressState = 2 // Show a progress indicator in error state
+	ProgressIndeterminate ProgressState = 3 // Show an indeterminate progress indicator
+	ProgressWarning       ProgressState = 4 // Show a progress indicator in warning (or paused) state
+)
+
+// ClearProgress removes the progress indicator.
+const ClearProgress = OSC + "9;4;0" + StringTerminator
+
+// SetProgress creates an OSC 9;4 sequence that sets the progress indicator shown in the terminal's tab or
+// the task bar to state and percent. percent is clamped to 0..100 and omitted for ProgressHidden and
+// ProgressIndeterminate. This sequence is supported by Windows Terminal, ConEmu, ghostty and others.
+func SetProgress(state ProgressState, percent int) string {
+	if state == ProgressHidden || state == ProgressIndeterminate {
+		return fmt.Sprintf("%s9;4;%d%s", OSC, state, StringTerminator)
+	}
+
+	if percent < 0 {
+		percent = 0
+	} else if percent > 100 {
+		percent = 100
+	}
+
+	return fmt.Sprintf("%s9;4;%d;%d%s", OSC, state, percent, StringTerminator)
+}
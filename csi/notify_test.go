@@ -0,0 +1,40 @@
+package csi
+
+import (
+	"testing"
+
+	"github.com/halimath/expect"
+	"github.com/halimath/expect/is"
+)
+
+func TestNotify(t *testing.T) {
+	expect.That(t,
+		is.EqualTo(Notify("build done"), "\x1b]9;build done\x1b\\"),
+		is.EqualTo(Notify("build\x1b]2;pwned\a done"), "\x1b]9;build]2;pwned done\x1b\\"),
+	)
+}
+
+func TestNotifyDesktop(t *testing.T) {
+	expect.That(t,
+		is.EqualTo(NotifyDesktop("build", "done"), "\x1b]777;notify;build;done\x1b\\"),
+		is.EqualTo(NotifyDesktop("a;b", "c;d\n"), "\x1b]777;notify;a,b;c;d\x1b\\"),
+	)
+}
+
+func TestKittyNotification(t *testing.T) {
+	expect.That(t,
+		is.EqualTo(KittyNotification("", "done", ""), "\x1b]99;d=1:p=title:e=1;ZG9uZQ==\x1b\\"),
+		is.EqualTo(KittyNotification("b:1", "done", "ok"),
+			"\x1b]99;i=b1:d=0:p=title:e=1;ZG9uZQ==\x1b\\\x1b]99;i=b1:d=1:p=body:e=1;b2s=\x1b\\"),
+	)
+}
+
+func TestSetProgress(t *testing.T) {
+	expect.That(t,
+		is.EqualTo(SetProgress(ProgressNormal, 42), "\x1b]9;4;1;42\x1b\\"),
+		is.EqualTo(SetProgress(ProgressError, 120), "\x1b]9;4;2;100\x1b\\"),
+		is.EqualTo(SetProgress(ProgressHidden, 0), "\x1b]9;4;0\x1b\\"),
+		is.EqualTo(SetProgress(ProgressHidden, 50), ClearProgress),
+		is.EqualTo(SetProgress(ProgressIndeterminate, 50), "\x1b]9;4;3\x1b\\"),
+	)
+}
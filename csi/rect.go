@@ -0,0 +1,58 @@
+package csi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// The functions below create sequences that operate on rectangular areas of the screen. They are
+// supported by VT420 and later compatible terminals (see DeviceAttributeRectangularEditing). All
+// coordinates are 1 based and inclusive.
+
+// FillRectangle formats a CSI to fill the rectangle from (left,top) to (right,bottom) with the character c
+// (DECFRA). The current character attributes are used for the filled cells.
+func FillRectangle(c rune, top, left, bottom, right int) string {
+	return fmt.Sprintf("%s%d;%d;%d;%d;%d$x", CSI, c, top, left, bottom, right)
+}
+
+// EraseRectangle formats a CSI to erase all characters in the rectangle from (left,top) to (right,bottom)
+// (DECERA).
+func EraseRectangle(top, left, bottom, right int) string {
+	return fmt.Sprintf("%s%d;%d;%d;%d$z", CSI, top, left, bottom, right)
+}
+
+// CopyRectangle formats a CSI to copy the rectangle from (left,top) to (right,bottom) to the area with its
+// upper left corner at (destLeft,destTop) (DECCRA).
+func CopyRectangle(top, left, bottom, right, destTop, destLeft int) string {
+	return fmt.Sprintf("%s%d;%d;%d;%d;1;%d;%d;1$v", CSI, top, left, bottom, right, destTop, destLeft)
+}
+
+// ChangeRectangleAttributes formats a CSI to change the character attributes of all cells in the rectangle
+// from (left,top) to (right,bottom) (DECCARA). attrs are SGR parameters; only 0 (reset), 1 (bold),
+// 4 (underline), 5 (blink), 7 (invert) and the corresponding resets 22, 24, 25 and 27 are supported.
+func ChangeRectangleAttributes(top, left, bottom, right int, attrs ...int) string {
+	return fmt.Sprintf("%s%d;%d;%d;%d%s$r", CSI, top, left, bottom, right, joinParams(attrs))
+}
+
+// ReverseRectangleAttributes formats a CSI to toggle the character attributes of all cells in the
+// rectangle from (left,top) to (right,bottom) (DECRARA). attrs are SGR parameters; only 0 (all), 1 (bold),
+// 4 (underline), 5 (blink) and 7 (invert) are supported.
+func ReverseRectangleAttributes(top, left, bottom, right int, attrs ...int) string {
+	return fmt.Sprintf("%s%d;%d;%d;%d%s$t", CSI, top, left, bottom, right, joinParams(attrs))
+}
+
+// Repeat formats a CSI to repeat the preceding graphic character n times (REP).
+func Repeat(n int) string {
+	return fmt.Sprintf("%s%db", CSI, n)
+}
+
+// joinParams formats params as a list of parameters each being prefixed with a semicolon.
+func joinParams(params []int) string {
+	var b strings.Builder
+	for _, p := range params {
+		b.WriteByte(';')
+		b.WriteString(strconv.Itoa(p))
+	}
+	return b.String()
+}
@@ -0,0 +1,35 @@
+package csi
+
+import (
+	"testing"
+
+	"github.com/halimath/expect"
+	"github.com/halimath/expect/is"
+)
+
+func TestFillRectangle(t *testing.T) {
+	expect.That(t, is.EqualTo(FillRectangle('x', 1, 2, 3, 4), "\x1b[120;1;2;3;4$x"))
+}
+
+func TestEraseRectangle(t *testing.T) {
+	expect.That(t, is.EqualTo(EraseRectangle(1, 2, 3, 4), "\x1b[1;2;3;4$z"))
+}
+
+func TestCopyRectangle(t *testing.T) {
+	expect.That(t, is.EqualTo(CopyRectangle(1, 2, 3, 4, 5, 6), "\x1b[1;2;3;4;1;5;6;1$v"))
+}
+
+func TestChangeRectangleAttributes(t *testing.T) {
+	expect.That(t,
+		is.EqualTo(ChangeRectangleAttributes(1, 2, 3, 4, 1, 7), "\x1b[1;2;3;4;1;7$r"),
+		is.EqualTo(ChangeRectangleAttributes(1, 2, 3, 4), "\x1b[1;2;3;4$r"),
+	)
+}
+
+func TestReverseRectangleAttributes(t *testing.T) {
+	expect.That(t, is.EqualTo(ReverseRectangleAttributes(1, 2, 3, 4, 0), "\x1b[1;2;3;4;0$t"))
+}
+
+func TestRepeat(t *testing.T) {
+	expect.That(t, is.EqualTo(Repeat(5), "\x1b[5b"))
+}
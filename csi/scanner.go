@@ -0,0 +1,157 @@
+package csi
+
+// scanState defines the state of a controlScanner.
+type scanState int
+
+const (
+	scanGround              scanState = iota // plain text
+	scanEscape                               // after ESC
+	scanEscapeIntermediate                   // after ESC and intermediate bytes
+	scanControlSequence                      // after CSI
+	scanControlString                        // after OSC, DCS, SOS, PM or APC
+	scanControlStringEscape                  // after ESC within a control string
+	scanControlStringC1                      // after the first byte of a C1 control within a control string
+	scanC1                                   // after the first byte of a UTF-8 encoded C1 control
+)
+
+// c1Lead is the first byte of the UTF-8 encoding of all C1 control characters (U+0080 to U+009F).
+const c1Lead = 0xc2
+
+// controlScanner implements a byte-wise parser for the control functions defined by ECMA-48. It tells apart
+// plain text from control functions without buffering, so arbitrarily long control strings (such as images)
+// can be processed in a streaming fashion.
+type controlScanner struct {
+	state scanState
+}
+
+// scan processes the single byte c and passes it to emit together with whether it is part of the plain
+// text (text is true) or of a control function (text is false). Horizontal tab, line feed, vertical tab,
+// form feed and carriage return are considered text. The first byte of a UTF-8 encoded C1 control is held
+// back until the following byte has been scanned. Every byte is emitted exactly once.
+func (s *controlScanner) scan(c byte, emit func(b byte, text bool)) {
+	switch s.state {
+	case scanGround:
+		switch {
+		case c == ESC[0]:
+			s.state = scanEscape
+			emit(c, false)
+		case c == c1Lead:
+			s.state = scanC1
+		case c == '\t', c == '\n', c == '\v', c == '\f', c == '\r':
+			emit(c, true)
+		case c < 0x20, c == 0x7f:
+			emit(c, false)
+		default:
+			emit(c, true)
+		}
+
+	case scanC1:
+		if c < 0x80 || c > 0x9f {
+			// Not a C1 control but some other character.
+			s.state = scanGround
+			emit(c1Lead, true)
+			s.scan(c, emit)
+			return
+		}
+
+		emit(c1Lead, false)
+		emit(c, false)
+
+		switch c {
+		case 0x9b:
+			s.state = scanControlSequence
+		case 0x90, 0x98, 0x9d, 0x9e, 0x9f:
+			s.state = scanControlString
+		default:
+			s.state = scanGround
+		}
+
+	case scanEscape:
+		switch {
+		case c == '[':
+			s.state = scanControlSequence
+		case c == ']', c == 'P', c == 'X', c == '^', c == '_':
+			s.state = scanControlString
+		case c == ESC[0]:
+			// Restart the escape sequence.
+		case c >= 0x20 && c <= 0x2f:
+			s.state = scanEscapeIntermediate
+		case c >= 0x30 && c <= 0x7e:
+			s.state = scanGround
+		default:
+			// Invalid escape sequence: process c as text.
+			s.state = scanGround
+			s.scan(c, emit)
+			return
+		}
+		emit(c, false)
+
+	case scanEscapeIntermediate:
+		switch {
+		case c >= 0x20 && c <= 0x2f:
+		case c >= 0x30 && c <= 0x7e:
+			s.state = scanGround
+		default:
+			s.state = scanGround
+			s.scan(c, emit)
+			return
+		}
+		emit(c, false)
+
+	case scanControlSequence:
+		switch {
+		case c >= 0x40 && c <= 0x7e:
+			s.state = scanGround
+		case c == ESC[0]:
+			// The control sequence is canceled by a new escape sequence.
+			s.state = scanEscape
+		case c < 0x40:
+			// Parameter bytes, intermediate bytes and C0 controls
+		default:
+			// Invalid control sequence: process c as text.
+			s.state = scanGround
+			s.scan(c, emit)
+			return
+		}
+		emit(c, false)
+
+	case scanControlString:
+		switch c {
+		case '\a':
+			s.state = scanGround
+		case ESC[0]:
+			s.state = scanControlStringEscape
+		case c1Lead:
+			s.state = scanControlStringC1
+		}
+		emit(c, false)
+
+	case scanControlStringEscape:
+		if c == '\\' {
+			s.state = scanGround
+			emit(c, false)
+			return
+		}
+		// The control string is canceled by a new escape sequence.
+		s.state = scanEscape
+		s.scan(c, emit)
+
+	case scanControlStringC1:
+		if c == 0x9c {
+			s.state = scanGround
+			emit(c, false)
+			return
+		}
+		s.state = scanControlString
+		s.scan(c, emit)
+	}
+}
+
+// flush emits the first byte of a UTF-8 sequence held back while waiting for the following byte as text and
+// discards any incomplete control function, i.e. the next byte scanned is considered to start plain text.
+func (s *controlScanner) flush(emit func(b byte, text bool)) {
+	if s.state == scanC1 {
+		emit(c1Lead, true)
+	}
+	s.state = scanGround
+}
@@ -0,0 +1,85 @@
+package csi
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const (
+	// Semantic prompt marks (OSC 133, also known as FinalTerm shell integration). Terminals use these marks
+	// to distinguish the prompt, the command typed by the user and the command's output, i.e. to allow
+	// jumping between prompts or selecting a command's output.
+	PromptStart     = OSC + "133;A" + StringTerminator // Marks the start of the prompt
+	PromptEnd       = OSC + "133;B" + StringTerminator // Marks the end of the prompt and the start of the user's input
+	CommandExecuted = OSC + "133;C" + StringTerminator // Marks the end of the user's input and the start of the command's output
+
+	// Shell integration marks defined by Visual Studio Code (OSC 633). These are extensions to the semantic
+	// prompt marks above.
+	VSCodePromptStart     = OSC + "633;A" + StringTerminator // Marks the start of the prompt
+	VSCodePromptEnd       = OSC + "633;B" + StringTerminator // Marks the end of the prompt and the start of the user's input
+	VSCodeCommandExecuted = OSC + "633;C" + StringTerminator // Marks the end of the user's input and the start of the command's output
+)
+
+// CommandFinished creates an OSC 133 sequence marking the end of a command's output. exitCode is the
+// command's exit code.
+func CommandFinished(exitCode int) string {
+	return fmt.Sprintf("%s133;D;%d%s", OSC, exitCode, StringTerminator)
+}
+
+// ReportWorkingDirectory creates an OSC 7 sequence that reports path as the current working directory
+// on host to the terminal. The directory is reported as a file URL with path being percent-encoded.
+// Terminals use this information i.e. to open new tabs in the same directory.
+func ReportWorkingDirectory(host, path string) string {
+	u := url.URL{
+		Scheme: "file",
+		Host:   host,
+		Path:   path,
+	}
+
+	return fmt.Sprintf("%s7;%s%s", OSC, u.String(), StringTerminator)
+}
+
+// VSCodeCommandFinished creates an OSC 633 sequence marking the end of a command's output. exitCode is the
+// command's exit code.
+func VSCodeCommandFinished(exitCode int) string {
+	return fmt.Sprintf("%s633;D;%d%s", OSC, exitCode, StringTerminator)
+}
+
+// VSCodeCommandLine creates an OSC 633 sequence that explicitly reports the command line being executed.
+// nonce is optional; if given, it must match the nonce passed to the shell integration script by VS Code
+// in order for the command line to be trusted.
+func VSCodeCommandLine(commandLine, nonce string) string {
+	if nonce == "" {
+		return fmt.Sprintf("%s633;E;%s%s", OSC, escapeVSCodeValue(commandLine), StringTerminator)
+	}
+
+	return fmt.Sprintf("%s633;E;%s;%s%s", OSC, escapeVSCodeValue(commandLine), escapeVSCodeValue(nonce), StringTerminator)
+}
+
+// VSCodeProperty creates an OSC 633 sequence that sets the shell integration property key to value. The
+// most common property is Cwd, which reports the current working directory.
+func VSCodeProperty(key, value string) string {
+	return fmt.Sprintf("%s633;P;%s=%s%s", OSC, escapeVSCodeValue(key), escapeVSCodeValue(value), StringTerminator)
+}
+
+// escapeVSCodeValue escapes s as required by VS Code: backslashes are doubled, semicolons and all
+// characters up to and including space are encoded as \xAB.
+func escapeVSCodeValue(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '\\':
+			b.WriteString(`\\`)
+		case c == ';' || c <= 0x20 || c == 0x7f:
+			fmt.Fprintf(&b, `\x%02x`, c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return b.String()
+}
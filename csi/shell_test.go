@@ -0,0 +1,35 @@
+package csi
+
+import (
+	"testing"
+
+	"github.com/halimath/expect"
+	"github.com/halimath/expect/is"
+)
+
+func TestCommandFinished(t *testing.T) {
+	expect.That(t, is.EqualTo(CommandFinished(1), "\x1b]133;D;1\x1b\\"))
+}
+
+func TestReportWorkingDirectory(t *testing.T) {
+	expect.That(t,
+		is.EqualTo(ReportWorkingDirectory("host", "/home/user"), "\x1b]7;file://host/home/user\x1b\\"),
+		is.EqualTo(ReportWorkingDirectory("host", "/tmp/my dir/ä"), "\x1b]7;file://host/tmp/my%20dir/%C3%A4\x1b\\"),
+		is.EqualTo(ReportWorkingDirectory("", "/tmp/\x1b"), "\x1b]7;file:///tmp/%1B\x1b\\"),
+	)
+}
+
+func TestVSCodeCommandFinished(t *testing.T) {
+	expect.That(t, is.EqualTo(VSCodeCommandFinished(0), "\x1b]633;D;0\x1b\\"))
+}
+
+func TestVSCodeCommandLine(t *testing.T) {
+	expect.That(t,
+		is.EqualTo(VSCodeCommandLine(`echo a;b\c`, ""), "\x1b]633;E;echo\\x20a\\x3bb\\\\c\x1b\\"),
+		is.EqualTo(VSCodeCommandLine("ls", "abc"), "\x1b]633;E;ls;abc\x1b\\"),
+	)
+}
+
+func TestVSCodeProperty(t *testing.T) {
+	expect.That(t, is.EqualTo(VSCodeProperty("Cwd", "/tmp/a b"), "\x1b]633;P;Cwd=/tmp/a\\x20b\x1b\\"))
+}
@@ -0,0 +1,98 @@
+package csi
+
+import (
+	"bytes"
+	"io"
+)
+
+// Strip removes all control functions as defined by ECMA-48 from b and returns the plain text. This
+// includes
+//
+//   - control sequences (CSI ... final byte), such as SGRs or cursor movements
+//   - control strings (OSC, DCS, SOS, PM and APC terminated by ST or BEL), such as window titles,
+//     hyperlinks (the link's text is kept) or images
+//   - escape sequences (ESC, optional intermediate bytes and a final byte), such as DECSC or character
+//     set designations
+//   - C1 control characters (encoded as UTF-8), including 8-bit variants of CSI, OSC, DCS, ...
+//   - C0 control characters except for horizontal tab, line feed, vertical tab, form feed and carriage
+//     return
+//
+// Use Strip to sanitize output written to a destination that is not a terminal.
+func Strip(b []byte) []byte {
+	if !containsControl(b) {
+		return b
+	}
+
+	var buf bytes.Buffer
+	buf.Grow(len(b))
+
+	s := StripWriter{w: &buf}
+	s.Write(b)
+	s.Flush()
+
+	return buf.Bytes()
+}
+
+// containsControl returns whether b contains any byte that may start a control function.
+func containsControl(b []byte) bool {
+	for _, c := range b {
+		if c < 0x20 || c == 0x7f || c == c1Lead {
+			return true
+		}
+	}
+	return false
+}
+
+// StripWriter is an io.Writer that removes all control functions (see Strip) from the bytes written to it
+// before writing them to an underlying io.Writer. Control functions split across multiple calls to Write
+// are handled correctly. StripWriter does not buffer control functions, so arbitrarily long control strings
+// (such as images) can be stripped.
+type StripWriter struct {
+	w       io.Writer
+	scanner controlScanner
+	buf     []byte
+}
+
+// NewStripWriter creates a new StripWriter writing to w.
+func NewStripWriter(w io.Writer) *StripWriter {
+	return &StripWriter{w: w}
+}
+
+// Write writes p to the underlying io.Writer with all control functions removed. It returns len(p) unless
+// an error occured.
+func (s *StripWriter) Write(p []byte) (int, error) {
+	s.buf = s.buf[:0]
+
+	for _, c := range p {
+		s.scanner.scan(c, s.emit)
+	}
+
+	if len(s.buf) > 0 {
+		if _, err := s.w.Write(s.buf); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(p), nil
+}
+
+// Flush writes a partial UTF-8 sequence held back while waiting for the next byte to the underlying
+// io.Writer. Incomplete control functions are discarded.
+func (s *StripWriter) Flush() error {
+	s.buf = s.buf[:0]
+	s.scanner.flush(s.emit)
+
+	if len(s.buf) == 0 {
+		return nil
+	}
+
+	_, err := s.w.Write(s.buf)
+	return err
+}
+
+// emit collects the plain text bytes to write.
+func (s *StripWriter) emit(b byte, text bool) {
+	if text {
+		s.buf = append(s.buf, b)
+	}
+}
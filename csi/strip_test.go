@@ -0,0 +1,76 @@
+package csi
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/halimath/expect"
+	"github.com/halimath/expect/is"
+)
+
+var stripTests = map[string]string{
+	"foobar":                        "foobar",
+	"line 1\r\n\tline 2\n":          "line 1\r\n\tline 2\n",
+	"\x1b[1;31mfoo\x1b[0m":          "foo",
+	"\x1b[2Jfoo bar m":              "foo bar m",
+	"\x1b[?1049hfoo\x1b[?1049l":     "foo",
+	"\x1b[0 qfoo":                   "foo",
+	SetWindowTitle("title") + "foo": "foo",
+	"\x1b]2;title\afoo":             "foo",
+	Hyperlink("https://example.com", "foo", "1") + "bar": "foobar",
+	"\x1bP$qm\x1b\\foo":         "foo",
+	"\x1b_Ga=T;AAAA\x1b\\foo":   "foo",
+	"\x1b7foo\x1b8":             "foo",
+	"\x1b(0qqq\x1b(B":           "qqq",
+	"\x1b#8foo":                 "foo",
+	"a\x07b\x08c\x00d\x7fe":     "abcde",
+	"\u009b31mfoo\u009b0m":      "foo",
+	"\u009d0;title\u009cfoo":    "foo",
+	"\u0085foo\u0084":           "foo",
+	"café ©":                    "café ©",
+	"\x1b[31":                   "",
+	"\x1b]0;unterminated":       "",
+	"\x1b]0;canceled\x1b[1mfoo": "foo",
+	"\x1bé":                     "é",
+}
+
+func TestStrip(t *testing.T) {
+	for in, want := range stripTests {
+		expect.WithMessage(t, "input %q", in).
+			That(is.EqualTo(string(Strip([]byte(in))), want))
+	}
+}
+
+func TestStripWriter(t *testing.T) {
+	for in, want := range stripTests {
+		// Write the input byte by byte to test sequences split across writes.
+		var buf bytes.Buffer
+		w := NewStripWriter(&buf)
+		for i := 0; i < len(in); i++ {
+			n, err := w.Write([]byte{in[i]})
+			expect.That(t, is.NoError(err), is.EqualTo(n, 1))
+		}
+		expect.That(t, is.NoError(w.Flush()))
+
+		expect.WithMessage(t, "input %q", in).
+			That(is.EqualTo(buf.String(), want))
+	}
+}
+
+func TestStripWriter_Flush(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewStripWriter(&buf)
+
+	_, err := w.Write([]byte("a\x1b]0;unterminated"))
+	expect.That(t, is.NoError(err), is.NoError(w.Flush()))
+
+	_, err = w.Write([]byte("b\x1b[1"))
+	expect.That(t, is.NoError(err), is.NoError(w.Flush()))
+
+	_, err = w.Write([]byte("c"))
+	expect.That(t,
+		is.NoError(err),
+		is.NoError(w.Flush()),
+		is.EqualTo(buf.String(), "abc"),
+	)
+}
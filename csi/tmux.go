@@ -0,0 +1,33 @@
+package csi
+
+import (
+	"io"
+	"strings"
+)
+
+// TmuxPassthrough wraps seq in a DCS passthrough sequence which makes tmux forward seq to the outer
+// terminal unchanged. This is required for sequences tmux does not understand itself, such as OSC 52 or
+// graphics protocols. Note that tmux only forwards passthrough sequences when the allow-passthrough option
+// is enabled.
+func TmuxPassthrough(seq string) string {
+	return DCS + "tmux;" + strings.ReplaceAll(seq, ESC, ESC+ESC) + StringTerminator
+}
+
+// WithTmuxPassthrough returns an io.ReadWriter that wraps every write to rw using TmuxPassthrough. Reads
+// are delegated to rw unchanged. Use the returned value to issue queries (such as GetClipboard) from
+// within a tmux session.
+func WithTmuxPassthrough(rw io.ReadWriter) io.ReadWriter {
+	return &tmuxPassthrough{rw}
+}
+
+type tmuxPassthrough struct {
+	io.ReadWriter
+}
+
+func (t *tmuxPassthrough) Write(p []byte) (int, error) {
+	_, err := io.WriteString(t.ReadWriter, TmuxPassthrough(string(p)))
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
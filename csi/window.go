@@ -0,0 +1,173 @@
+package csi
+
+import (
+	"fmt"
+	"io"
+)
+
+const (
+	// Commands to save and restore the window title and icon name using the terminal's title stack
+	// (XTWINOPS). Push the current title before changing it and pop it when the application exits to
+	// leave the user's title intact.
+	PushWindowTitle = CSI + "22;0t" // Saves the icon name and window title on the stack
+	PopWindowTitle  = CSI + "23;0t" // Restores the icon name and window title from the stack
+)
+
+// SetWindowTitle creates a control sequence to set the window's title to title. Control characters are
+// removed from title so untrusted strings can not inject escape sequences.
+func SetWindowTitle(title string) string {
+	return fmt.Sprintf("%s2;%s%s", OSC, stripControlCharacters(title), StringTerminator)
+}
+
+// SetIconName creates a control sequence to set the window's icon name to name. Control characters are
+// removed from name so untrusted strings can not inject escape sequences.
+func SetIconName(name string) string {
+	return fmt.Sprintf("%s1;%s%s", OSC, stripControlCharacters(name), StringTerminator)
+}
+
+// SetIconNameAndWindowTitle creates a control sequence to set both the window's icon name and title to
+// title. Control characters are removed from title so untrusted strings can not inject escape sequences.
+func SetIconNameAndWindowTitle(title string) string {
+	return fmt.Sprintf("%s0;%s%s", OSC, stripControlCharacters(title), StringTerminator)
+}
+
+const (
+	queryWindowTitle = CSI + "21t"
+	queryIconName    = CSI + "20t"
+)
+
+// GetWindowTitle queries the window's title. Note that many terminals do not answer this query for
+// security reasons. Those cause this function to block until some other input is read.
+func GetWindowTitle(rw io.ReadWriter) (string, error) {
+	return execStringQuery(rw, queryWindowTitle, 1024, titleResponseHandler('l', "get window title"))
+}
+
+// GetIconName queries the window's icon name. Note that many terminals do not answer this query for
+// security reasons. Those cause this function to block until some other input is read.
+func GetIconName(rw io.ReadWriter) (string, error) {
+	return execStringQuery(rw, queryIconName, 1024, titleResponseHandler('L', "get icon name"))
+}
+
+// titleResponseHandler creates a queryHandler that handles a response of the form OSC <kind> <title> ST.
+func titleResponseHandler(kind byte, op string) queryHandler[string] {
+	return func(res []byte) (string, error) {
+		payload, ok := trimOSC(res)
+		if !ok || len(payload) == 0 || payload[0] != kind {
+			return "", fmt.Errorf("%w: %s: %q", ErrInvalidTerminalResponse, op, res)
+		}
+
+		return string(payload[1:]), nil
+	}
+}
+
+const (
+	// Commands to manipulate the terminal window (XTWINOPS). Note that many terminals ignore some or all
+	// of these commands or require them to be enabled by the user.
+	IconifyWindow            = CSI + "2t"    // Iconifies (minimizes) the window
+	DeiconifyWindow          = CSI + "1t"    // De-iconifies (restores) the window
+	RaiseWindow              = CSI + "5t"    // Raises the window to the front of the stacking order
+	LowerWindow              = CSI + "6t"    // Lowers the window to the bottom of the stacking order
+	RefreshWindow            = CSI + "7t"    // Refreshes the window
+	MaximizeWindow           = CSI + "9;1t"  // Maximizes the window
+	MaximizeWindowVertical   = CSI + "9;2t"  // Maximizes the window vertically
+	MaximizeWindowHorizontal = CSI + "9;3t"  // Maximizes the window horizontally
+	UnmaximizeWindow         = CSI + "9;0t"  // Restores a maximized window
+	EnterFullscreen          = CSI + "10;1t" // Switches the window to fullscreen mode
+	ExitFullscreen           = CSI + "10;0t" // Leaves fullscreen mode
+	ToggleFullscreen         = CSI + "10;2t" // Toggles fullscreen mode
+)
+
+// MoveWindow formats a CSI to move the window's upper left corner to the screen position (x,y) given in
+// pixels.
+func MoveWindow(x, y int) string {
+	return fmt.Sprintf("%s3;%d;%dt", CSI, x, y)
+}
+
+// ResizeWindow formats a CSI to resize the window's text area to width columns and height rows. Pass 0 for
+// either to keep the current size in that dimension.
+func ResizeWindow(width, height int) string {
+	return fmt.Sprintf("%s8;%d;%dt", CSI, height, width)
+}
+
+// ResizeWindowPixels formats a CSI to resize the window's text area to width x height pixels. Pass 0 for
+// either to use the display's size in that dimension.
+func ResizeWindowPixels(width, height int) string {
+	return fmt.Sprintf("%s4;%d;%dt", CSI, height, width)
+}
+
+const (
+	queryWindowState     = CSI + "11t"
+	queryWindowPosition  = CSI + "13t"
+	queryWindowPixelSize = CSI + "14t"
+	queryCellPixelSize   = CSI + "16t"
+	queryWindowSize      = CSI + "18t"
+)
+
+// IsWindowIconified queries whether the window is iconified (minimized).
+func IsWindowIconified(rw io.ReadWriter) (bool, error) {
+	params, err := execQuery(rw, queryWindowState, 32, windowOpsResponseHandler("get window state", 1, 1, 2))
+	if err != nil {
+		return false, err
+	}
+
+	return params[0] == 2, nil
+}
+
+// GetWindowPosition queries the position of the window's upper left corner on the screen in pixels.
+func GetWindowPosition(rw io.ReadWriter) (x, y int, err error) {
+	params, err := execQuery(rw, queryWindowPosition, 64, windowOpsResponseHandler("get window position", 3, 3))
+	if err != nil {
+		return
+	}
+	return params[1], params[2], nil
+}
+
+// GetWindowSize queries the size of the window's text area in columns and rows.
+func GetWindowSize(rw io.ReadWriter) (width, height int, err error) {
+	params, err := execQuery(rw, queryWindowSize, 64, windowOpsResponseHandler("get window size", 3, 8))
+	if err != nil {
+		return
+	}
+	return params[2], params[1], nil
+}
+
+// GetWindowPixelSize queries the size of the window's text area in pixels.
+func GetWindowPixelSize(rw io.ReadWriter) (width, height int, err error) {
+	params, err := execQuery(rw, queryWindowPixelSize, 64, windowOpsResponseHandler("get window pixel size", 3, 4))
+	if err != nil {
+		return
+	}
+	return params[2], params[1], nil
+}
+
+// GetCellPixelSize queries the size of a single character cell in pixels.
+func GetCellPixelSize(rw io.ReadWriter) (width, height int, err error) {
+	params, err := execQuery(rw, queryCellPixelSize, 64, windowOpsResponseHandler("get cell pixel size", 3, 6))
+	if err != nil {
+		return
+	}
+	return params[2], params[1], nil
+}
+
+// windowOpsResponseHandler creates a queryHandler that handles a XTWINOPS response of the form
+// CSI Ps ; ... t. The response must contain exactly numParams parameters and the first parameter must be one
+// of kinds. Negative values are only accepted for window position reports (3) as the window may be placed
+// left of or above the primary monitor.
+func windowOpsResponseHandler(op string, numParams int, kinds ...int) queryHandler[[]int] {
+	return func(res []byte) ([]int, error) {
+		params, ok := parseCSIResponse(res, "", "t")
+		if !ok || len(params) != numParams || !containsInt(kinds, params[0]) {
+			return nil, fmt.Errorf("%w: %s: %q", ErrInvalidTerminalResponse, op, res)
+		}
+
+		if params[0] != 3 {
+			for _, p := range params {
+				if p < 0 {
+					return nil, fmt.Errorf("%w: %s: %q", ErrInvalidTerminalResponse, op, res)
+				}
+			}
+		}
+
+		return params, nil
+	}
+}
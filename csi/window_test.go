@@ -0,0 +1,182 @@
+package csi
+
+import (
+	"testing"
+
+	"github.com/halimath/expect"
+	"github.com/halimath/expect/is"
+)
+
+func TestSetWindowTitle(t *testing.T) {
+	expect.That(t,
+		is.EqualTo(SetWindowTitle("hello, world"), "\x1b]2;hello, world\x1b\\"),
+		is.EqualTo(SetWindowTitle("hello\x1b\\\x1b[2J\a"), "\x1b]2;hello\\[2J\x1b\\"),
+	)
+}
+
+func TestSetIconName(t *testing.T) {
+	expect.That(t, is.EqualTo(SetIconName("hello\u009c"), "\x1b]1;hello\x1b\\"))
+}
+
+func TestSetIconNameAndWindowTitle(t *testing.T) {
+	expect.That(t, is.EqualTo(SetIconNameAndWindowTitle("hello"), "\x1b]0;hello\x1b\\"))
+}
+
+func TestGetWindowTitle(t *testing.T) {
+	t.Run("validResponse", func(t *testing.T) {
+		var rw rw
+		rw.r.WriteString("\x1b]lhello, world\x1b\\")
+
+		got, err := GetWindowTitle(&rw)
+		expect.That(t,
+			is.NoError(err),
+			is.EqualTo(got, "hello, world"),
+			is.EqualTo(rw.w.String(), "\x1b[21t"),
+		)
+	})
+
+	t.Run("invalidResponse", func(t *testing.T) {
+		var rw rw
+		rw.r.WriteString("\x1b]Lhello\x1b\\")
+
+		_, err := GetWindowTitle(&rw)
+		expect.That(t, is.Error(err, ErrInvalidTerminalResponse))
+	})
+}
+
+func TestGetIconName(t *testing.T) {
+	var rw rw
+	rw.r.WriteString("\x1b]Licon\a")
+
+	got, err := GetIconName(&rw)
+	expect.That(t,
+		is.NoError(err),
+		is.EqualTo(got, "icon"),
+	)
+}
+
+func TestMoveWindow(t *testing.T) {
+	expect.That(t, is.EqualTo(MoveWindow(10, 20), "\x1b[3;10;20t"))
+}
+
+func TestResizeWindow(t *testing.T) {
+	expect.That(t, is.EqualTo(ResizeWindow(80, 24), "\x1b[8;24;80t"))
+}
+
+func TestResizeWindowPixels(t *testing.T) {
+	expect.That(t, is.EqualTo(ResizeWindowPixels(800, 600), "\x1b[4;600;800t"))
+}
+
+func TestIsWindowIconified(t *testing.T) {
+	t.Run("notIconified", func(t *testing.T) {
+		var rw rw
+		rw.r.WriteString("\x1b[1t")
+
+		got, err := IsWindowIconified(&rw)
+		expect.That(t,
+			is.NoError(err),
+			is.EqualTo(got, false),
+			is.EqualTo(rw.w.String(), "\x1b[11t"),
+		)
+	})
+
+	t.Run("iconified", func(t *testing.T) {
+		var rw rw
+		rw.r.WriteString("\x1b[2t")
+
+		got, err := IsWindowIconified(&rw)
+		expect.That(t,
+			is.NoError(err),
+			is.EqualTo(got, true),
+		)
+	})
+
+	t.Run("invalidResponse", func(t *testing.T) {
+		var rw rw
+		rw.r.WriteString("\x1b[3t")
+
+		_, err := IsWindowIconified(&rw)
+		expect.That(t, is.Error(err, ErrInvalidTerminalResponse))
+	})
+}
+
+func TestGetWindowPosition(t *testing.T) {
+	t.Run("validResponse", func(t *testing.T) {
+		var rw rw
+		rw.r.WriteString("\x1b[3;10;20t")
+
+		x, y, err := GetWindowPosition(&rw)
+		expect.That(t,
+			is.NoError(err),
+			is.EqualTo(x, 10),
+			is.EqualTo(y, 20),
+		)
+	})
+
+	t.Run("negativeCoordinate", func(t *testing.T) {
+		var rw rw
+		rw.r.WriteString("\x1b[3;-1920;20t")
+
+		x, y, err := GetWindowPosition(&rw)
+		expect.That(t,
+			is.NoError(err),
+			is.EqualTo(x, -1920),
+			is.EqualTo(y, 20),
+		)
+	})
+}
+
+func TestGetWindowSize(t *testing.T) {
+	t.Run("validResponse", func(t *testing.T) {
+		var rw rw
+		rw.r.WriteString("\x1b[8;24;80t")
+
+		w, h, err := GetWindowSize(&rw)
+		expect.That(t,
+			is.NoError(err),
+			is.EqualTo(w, 80),
+			is.EqualTo(h, 24),
+			is.EqualTo(rw.w.String(), "\x1b[18t"),
+		)
+	})
+
+	t.Run("invalidResponse", func(t *testing.T) {
+		var rw rw
+		rw.r.WriteString("\x1b[4;24;80t")
+
+		_, _, err := GetWindowSize(&rw)
+		expect.That(t, is.Error(err, ErrInvalidTerminalResponse))
+	})
+
+	t.Run("negativeSize", func(t *testing.T) {
+		var rw rw
+		rw.r.WriteString("\x1b[8;-24;80t")
+
+		_, _, err := GetWindowSize(&rw)
+		expect.That(t, is.Error(err, ErrInvalidTerminalResponse))
+	})
+}
+
+func TestGetWindowPixelSize(t *testing.T) {
+	var rw rw
+	rw.r.WriteString("\x1b[4;600;800t")
+
+	w, h, err := GetWindowPixelSize(&rw)
+	expect.That(t,
+		is.NoError(err),
+		is.EqualTo(w, 800),
+		is.EqualTo(h, 600),
+	)
+}
+
+func TestGetCellPixelSize(t *testing.T) {
+	var rw rw
+	rw.r.WriteString("\x1b[6;16;8t")
+
+	w, h, err := GetCellPixelSize(&rw)
+	expect.That(t,
+		is.NoError(err),
+		is.EqualTo(w, 8),
+		is.EqualTo(h, 16),
+	)
+}
@@ -8,6 +8,18 @@ import (
 	"github.com/halimath/terminal/sgr"
 )
 
+var attributes = []sgr.SGR{
+	sgr.Bold,
+	sgr.Faint,
+	sgr.Italic,
+	sgr.Underlined,
+	sgr.UnderlinedCurly,
+	sgr.Blink,
+	sgr.Invert,
+	sgr.Strike,
+	sgr.Overline,
+}
+
 var fgColors = []sgr.SGR{
 	sgr.FgBlack,
 	sgr.FgRed,
@@ -78,4 +90,10 @@ func main() {
 	fmt.Fprintf(t, "TrueColor [%v]:\t", terminal.IsTruecolorSupported())
 	fmt.Fprint(t, sgr.BgTrueColor(120, 0, 0).Join(sgr.FgTrueColor(0, 120, 120)).Apply("This should be printed green on red"))
 	fmt.Fprintln(t)
+
+	t.WriteString("Attributes:\t\t")
+	for _, a := range attributes {
+		fmt.Fprint(t, a.Apply("aBc"), " ")
+	}
+	fmt.Fprintln(t)
 }
@@ -46,25 +46,22 @@ func main() {
 	t.Print(csi.SetCursorPosition(1, 1))
 
 	t.Print(sgr.Bold.Apply("github.com/halimath/termx input example application"))
-	t.Print(csi.MoveCursorBackward(200))
-	t.Print(csi.MoveCursorDown(1))
+	t.Print(csi.CursorNextLine(1))
 
 	t.Printf("Application Mode: %s; Alternative Buffer: %s, Mouse Tracking: %s",
 		sgr.Bold.Applyf("%v", *useApplicationMode),
 		sgr.Bold.Applyf("%v", *useAlternateScreenBuffer),
 		sgr.Bold.Applyf("%v", *enableMouse),
 	)
-	t.Print(csi.MoveCursorBackward(200))
-	t.Print(csi.MoveCursorDown(1))
+	t.Print(csi.CursorNextLine(1))
 
 	t.Print(sgr.Faint.Apply("Press any key to see its internal representation; press C-x to display Bg color info; press C-c to quit"))
-	t.Print(csi.MoveCursorBackward(200))
-	t.Print(csi.MoveCursorDown(1))
+	t.Print(csi.CursorNextLine(1))
 
 	for {
 		evt, raw, err := t.ReadInputEvent()
 
-		t.Print(csi.MoveCursorBackward(200))
+		t.Print(csi.CursorHorizontalAbsolute(1))
 		t.Print(csi.ClearLine)
 
 		t.Print(sgr.FgCyan.Apply(evt))
@@ -87,8 +84,7 @@ func main() {
 				panic(err)
 			}
 
-			t.Print(csi.MoveCursorBackward(200))
-			t.Print(csi.MoveCursorDown(1))
+			t.Print(csi.CursorNextLine(1))
 			t.Print(csi.ClearLine)
 			t.Printf("(%d, %d, %d) %v", r, g, b, err)
 			t.Print(csi.MoveCursorUp(1))
@@ -97,8 +93,7 @@ func main() {
 		if evt == input.Ctrl('v') {
 			x, y, err := csi.GetCursorPosition(t)
 
-			t.Print(csi.MoveCursorBackward(200))
-			t.Print(csi.MoveCursorDown(1))
+			t.Print(csi.CursorNextLine(1))
 			t.Print(csi.ClearLine)
 			t.Printf("(%d,%d) %v", x, y, err)
 			t.Print(csi.MoveCursorUp(1))
@@ -106,6 +101,6 @@ func main() {
 	}
 
 	t.Print(csi.MoveCursorUp(1))
-	t.Print(csi.MoveCursorBackward(200))
+	t.Print(csi.CursorHorizontalAbsolute(1))
 
 }
@@ -0,0 +1,326 @@
+package graphics
+
+import (
+	"image"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/halimath/terminal/csi"
+	"github.com/halimath/terminal/sgr"
+)
+
+// BlockMode defines how Blocks maps pixels to character cells.
+type BlockMode int
+
+const (
+	// HalfBlocks renders two vertically stacked pixels per cell using the upper half block character with
+	// the foreground color set to the upper and the background color set to the lower pixel.
+	HalfBlocks BlockMode = iota
+	// QuarterBlocks renders 2x2 pixels per cell using the quadrant block characters. As a cell can only
+	// show two colors, the four pixels are reduced to the two most different colors.
+	QuarterBlocks
+	// Braille renders 2x4 pixels per cell using braille patterns. This mode is monochrome: a dot is set
+	// for every opaque pixel with a luminance greater than or equal to the threshold.
+	Braille
+)
+
+// ColorDepth defines the colors available to render an image using Blocks. Colors are degraded using
+// sgr.ColorProfile.
+type ColorDepth int
+
+const (
+	TrueColor ColorDepth = iota // 24 bit RGB colors
+	Colors256                   // The 256 colors palette
+	Colors16                    // The 16 standard colors
+)
+
+// defaultBrailleThreshold is the luminance threshold used by Braille if none is given.
+const defaultBrailleThreshold = 128
+
+// Blocks renders images as text using Unicode block or braille characters. It serves as a fallback for
+// terminals that don't support any graphics protocol. The zero value renders the image using HalfBlocks
+// in true color with one pixel per half cell.
+type Blocks struct {
+	// Mode defines the characters to use.
+	Mode BlockMode
+
+	// Colors defines the colors to use. Colors are degraded to the nearest color available.
+	Colors ColorDepth
+
+	// Rows and Columns, if > 0, scale the image (preserving its aspect ratio) to occupy at most the given
+	// number of rows and columns.
+	Rows, Columns int
+
+	// Threshold defines the luminance threshold used by Braille. Defaults to 128.
+	Threshold uint8
+
+	// Repeat enables the use of REP (see csi.Repeat) to compress runs of identical cells. Only enable
+	// this for terminals supporting REP, i.e. those reporting csi.DeviceAttributeRectangularEditing.
+	Repeat bool
+}
+
+// Encode renders img into a string. Every row of cells ends with SGR reset and a line break.
+func (b Blocks) Encode(img image.Image) (string, error) {
+	sx, sy := b.pixelsPerCell()
+
+	n := toNRGBA(img)
+	w, h := n.Rect.Dx(), n.Rect.Dy()
+	if w == 0 || h == 0 {
+		return "", nil
+	}
+
+	// Cells are roughly twice as high as wide, so a pixel is displayed with an aspect ratio (height to
+	// width) of 2*sx/sy. Compensate that to preserve the image's aspect ratio.
+	h = h * sy / (2 * sx)
+	if h < 1 {
+		h = 1
+	}
+	w, h = fitSize(w, h, b.Columns*sx, b.Rows*sy)
+	n = resize(n, w, h)
+
+	var s strings.Builder
+	var last cell
+
+	for y := 0; y < h; y += sy {
+		last = cell{}
+		var run int
+
+		for x := 0; x < w; x += sx {
+			c := b.cell(n, x, y)
+			colorChanged := !sameColor(c.fg, last.fg) || !sameColor(c.bg, last.bg)
+
+			if b.Repeat && !colorChanged && c.r == last.r {
+				run++
+				continue
+			}
+
+			writeRepeated(&s, last.r, run)
+			run = 0
+
+			if colorChanged {
+				s.WriteString(b.escape(c))
+			}
+			s.WriteRune(c.r)
+			last = c
+		}
+
+		writeRepeated(&s, last.r, run)
+		s.WriteString(sgr.ResetAll.Escape())
+		s.WriteString("\r\n")
+	}
+
+	return s.String(), nil
+}
+
+// writeRepeated writes r n more times using either REP or plain repetition of r, whichever is shorter.
+func writeRepeated(s *strings.Builder, r rune, n int) {
+	if n == 0 {
+		return
+	}
+
+	if rep := csi.Repeat(n); len(rep) < n*utf8.RuneLen(r) {
+		s.WriteString(rep)
+		return
+	}
+
+	for i := 0; i < n; i++ {
+		s.WriteRune(r)
+	}
+}
+
+// Render implements Renderer. It works like Encode and writes the result to w.
+func (b Blocks) Render(w io.Writer, img image.Image) error {
+	return render(w, img, b.Encode)
+}
+
+func (b Blocks) pixelsPerCell() (int, int) {
+	switch b.Mode {
+	case QuarterBlocks:
+		return 2, 2
+	case Braille:
+		return 2, 4
+	default:
+		return 1, 2
+	}
+}
+
+// cell is a single character cell to render. fg and bg are nil if no color should be set.
+type cell struct {
+	r      rune
+	fg, bg *rgb
+}
+
+// sameColor returns whether a and b are both nil or point to the same color value.
+func sameColor(a, b *rgb) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func (b Blocks) cell(img *image.NRGBA, x, y int) cell {
+	switch b.Mode {
+	case QuarterBlocks:
+		return quarterBlockCell(img, x, y)
+	case Braille:
+		threshold := b.Threshold
+		if threshold == 0 {
+			threshold = defaultBrailleThreshold
+		}
+		return brailleCell(img, x, y, threshold)
+	default:
+		return halfBlockCell(img, x, y)
+	}
+}
+
+// pixelAt returns the color of the pixel at (x,y) or nil if the pixel is transparent or out of bounds.
+func pixelAt(img *image.NRGBA, x, y int) *rgb {
+	if !(image.Point{x, y}.In(img.Rect)) {
+		return nil
+	}
+
+	p := img.Pix[img.PixOffset(x, y):]
+	if p[3] < alphaThreshold {
+		return nil
+	}
+
+	return &rgb{p[0], p[1], p[2]}
+}
+
+func halfBlockCell(img *image.NRGBA, x, y int) cell {
+	top, bottom := pixelAt(img, x, y), pixelAt(img, x, y+1)
+
+	switch {
+	case top == nil && bottom == nil:
+		return cell{r: ' '}
+	case top == nil:
+		return cell{r: '▄', fg: bottom}
+	default:
+		return cell{r: '▀', fg: top, bg: bottom}
+	}
+}
+
+// quadrants contains the quadrant block characters indexed by a bit mask with the upper left quadrant
+// being bit 0, upper right bit 1, lower left bit 2 and lower right bit 3.
+var quadrants = [16]rune{' ', '▘', '▝', '▀', '▖', '▌', '▞', '▛', '▗', '▚', '▐', '▜', '▄', '▙', '▟', '█'}
+
+func quarterBlockCell(img *image.NRGBA, x, y int) cell {
+	pixels := [4]*rgb{pixelAt(img, x, y), pixelAt(img, x+1, y), pixelAt(img, x, y+1), pixelAt(img, x+1, y+1)}
+
+	var opaque [][3]int
+	var opaqueMask int
+	for i, p := range pixels {
+		if p != nil {
+			opaque = append(opaque, [3]int{int(p[0]), int(p[1]), int(p[2])})
+			opaqueMask |= 1 << i
+		}
+	}
+
+	if len(opaque) == 0 {
+		return cell{r: ' '}
+	}
+
+	if len(opaque) < len(pixels) {
+		// Transparent pixels can only be rendered using the terminal's background, so render all opaque
+		// pixels using a single foreground color.
+		return cell{r: quadrants[opaqueMask], fg: average(opaque)}
+	}
+
+	// Find the two most distant colors which become the foreground and background color.
+	a, b, dist := pixels[0], pixels[0], -1
+	for i, p := range pixels {
+		for _, q := range pixels[i+1:] {
+			if d := colorDistance(*p, *q); d > dist {
+				a, b, dist = p, q, d
+			}
+		}
+	}
+
+	var mask int
+	var fg, bg [][3]int
+	for i, p := range pixels {
+		if colorDistance(*p, *a) <= colorDistance(*p, *b) {
+			mask |= 1 << i
+			fg = append(fg, [3]int{int(p[0]), int(p[1]), int(p[2])})
+		} else {
+			bg = append(bg, [3]int{int(p[0]), int(p[1]), int(p[2])})
+		}
+	}
+
+	return cell{r: quadrants[mask], fg: average(fg), bg: average(bg)}
+}
+
+func average(colors [][3]int) *rgb {
+	if len(colors) == 0 {
+		return nil
+	}
+
+	var sum [3]int
+	for _, c := range colors {
+		for i := range sum {
+			sum[i] += c[i]
+		}
+	}
+	return &rgb{uint8(sum[0] / len(colors)), uint8(sum[1] / len(colors)), uint8(sum[2] / len(colors))}
+}
+
+// brailleDots contains the bit of each dot in a braille pattern indexed by [y][x].
+var brailleDots = [4][2]rune{{0x01, 0x08}, {0x02, 0x10}, {0x04, 0x20}, {0x40, 0x80}}
+
+func brailleCell(img *image.NRGBA, x, y int, threshold uint8) cell {
+	r := rune(0x2800)
+	for dy := 0; dy < 4; dy++ {
+		for dx := 0; dx < 2; dx++ {
+			if p := pixelAt(img, x+dx, y+dy); p != nil && luminance(*p) >= threshold {
+				r |= brailleDots[dy][dx]
+			}
+		}
+	}
+	return cell{r: r}
+}
+
+// luminance computes the relative luminance of c.
+func luminance(c rgb) uint8 {
+	return uint8((299*int(c[0]) + 587*int(c[1]) + 114*int(c[2])) / 1000)
+}
+
+func colorDistance(a, b rgb) int {
+	dr := int(a[0]) - int(b[0])
+	dg := int(a[1]) - int(b[1])
+	db := int(a[2]) - int(b[2])
+	return dr*dr + dg*dg + db*db
+}
+
+// escape creates the escape sequence to render c. Colors not set on c are reset to the terminal's default.
+func (b Blocks) escape(c cell) string {
+	fg, bg := sgr.DefaultFg, sgr.DefaultBg
+	if c.fg != nil {
+		fg = b.sgrColor(*c.fg, false)
+	}
+	if c.bg != nil {
+		bg = b.sgrColor(*c.bg, true)
+	}
+	return fg.Join(bg).Escape()
+}
+
+// sgrColor creates the SGR to set c as the foreground (or background) color degraded to b.Colors.
+func (b Blocks) sgrColor(c rgb, background bool) sgr.SGR {
+	col := b.Colors.profile().Convert(sgr.RGBColor{R: c[0], G: c[1], B: c[2]})
+	if background {
+		return col.Bg()
+	}
+	return col.Fg()
+}
+
+// profile returns the sgr.ColorProfile corresponding to d.
+func (d ColorDepth) profile() sgr.ColorProfile {
+	switch d {
+	case Colors256:
+		return sgr.ProfileANSI256
+	case Colors16:
+		return sgr.ProfileANSI
+	default:
+		return sgr.ProfileTrueColor
+	}
+}
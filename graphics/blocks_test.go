@@ -0,0 +1,98 @@
+package graphics
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/halimath/expect"
+	"github.com/halimath/expect/is"
+)
+
+func TestBlocks_Encode(t *testing.T) {
+	red := color.NRGBA{R: 255, A: 255}
+	blue := color.NRGBA{B: 255, A: 255}
+
+	t.Run("halfBlocks", func(t *testing.T) {
+		img := testImage(2, 2, red)
+		img.Set(0, 1, blue)
+		img.Set(1, 1, blue)
+
+		got, err := Blocks{}.Encode(img)
+		expect.That(t,
+			is.NoError(err),
+			is.EqualTo(got, "\x1b[38;2;255;0;0;48;2;0;0;255m▀▀\x1b[0m\r\n"),
+		)
+	})
+
+	t.Run("halfBlocksTransparent", func(t *testing.T) {
+		img := testImage(2, 2, red)
+		img.Set(0, 0, color.Transparent)
+		img.Set(1, 0, color.Transparent)
+		img.Set(1, 1, color.Transparent)
+
+		got, err := Blocks{}.Encode(img)
+		expect.That(t,
+			is.NoError(err),
+			is.EqualTo(got, "\x1b[38;2;255;0;0;49m▄\x1b[39;49m \x1b[0m\r\n"),
+		)
+	})
+
+	t.Run("colors256", func(t *testing.T) {
+		got, err := Blocks{Colors: Colors256}.Encode(testImage(1, 2, red))
+		expect.That(t,
+			is.NoError(err),
+			is.EqualTo(got, "\x1b[38;5;196;48;5;196m▀\x1b[0m\r\n"),
+		)
+	})
+
+	t.Run("colors16", func(t *testing.T) {
+		got, err := Blocks{Colors: Colors16}.Encode(testImage(1, 2, color.NRGBA{R: 200, G: 10, B: 10, A: 255}))
+		expect.That(t,
+			is.NoError(err),
+			is.EqualTo(got, "\x1b[31;41m▀\x1b[0m\r\n"),
+		)
+	})
+
+	t.Run("quarterBlocks", func(t *testing.T) {
+		img := testImage(2, 4, red)
+		for y := 0; y < 4; y++ {
+			img.Set(1, y, blue)
+		}
+
+		got, err := Blocks{Mode: QuarterBlocks}.Encode(img)
+		expect.That(t,
+			is.NoError(err),
+			is.EqualTo(got, "\x1b[38;2;255;0;0;48;2;0;0;255m▌\x1b[0m\r\n"),
+		)
+	})
+
+	t.Run("braille", func(t *testing.T) {
+		img := testImage(2, 8, color.White)
+		img.Set(1, 0, color.Black)
+
+		got, err := Blocks{Mode: Braille}.Encode(img)
+		expect.That(t,
+			is.NoError(err),
+			is.EqualTo(got, "⣷\x1b[0m\r\n⣿\x1b[0m\r\n"),
+		)
+	})
+
+	t.Run("scaled", func(t *testing.T) {
+		got, err := Blocks{Columns: 3, Rows: 1}.Encode(testImage(30, 20, red))
+		expect.That(t,
+			is.NoError(err),
+			is.EqualTo(got, "\x1b[38;2;255;0;0;48;2;255;0;0m▀▀▀\x1b[0m\r\n"),
+		)
+	})
+
+	t.Run("repeat", func(t *testing.T) {
+		img := testImage(8, 2, red)
+		img.Set(7, 0, blue)
+
+		got, err := Blocks{Repeat: true}.Encode(img)
+		expect.That(t,
+			is.NoError(err),
+			is.EqualTo(got, "\x1b[38;2;255;0;0;48;2;255;0;0m▀\x1b[6b\x1b[38;2;0;0;255;48;2;255;0;0m▀\x1b[0m\r\n"),
+		)
+	})
+}
@@ -0,0 +1,121 @@
+// Package graphics contains encoders that render images to a terminal using one of the graphics protocols
+// supported by modern terminal emulators.
+//
+// Like the csi package, the encoders in this package produce strings containing escape sequences which can
+// be written to a terminal. Functions that need to read a response from the terminal receive an
+// io.ReadWriter and use the query mechanism provided by the csi package.
+package graphics
+
+import (
+	"image"
+	"image/draw"
+	"io"
+	"os"
+
+	"github.com/halimath/terminal/csi"
+	"github.com/halimath/terminal/sgr"
+)
+
+// toNRGBA converts img to an *image.NRGBA with its bounds starting at (0,0). If img already is an
+// *image.NRGBA with such bounds, it is returned unchanged.
+func toNRGBA(img image.Image) *image.NRGBA {
+	if n, ok := img.(*image.NRGBA); ok && n.Rect.Min == (image.Point{}) {
+		return n
+	}
+
+	b := img.Bounds()
+	n := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(n, n.Rect, img, b.Min, draw.Src)
+	return n
+}
+
+// resize scales img to w x h pixels using nearest neighbor interpolation. If img already has the requested
+// size, it is returned unchanged.
+func resize(img *image.NRGBA, w, h int) *image.NRGBA {
+	sw, sh := img.Rect.Dx(), img.Rect.Dy()
+	if sw == w && sh == h {
+		return img
+	}
+
+	r := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		sy := y * sh / h
+		for x := 0; x < w; x++ {
+			sx := x * sw / w
+			copy(r.Pix[r.PixOffset(x, y):r.PixOffset(x, y)+4], img.Pix[img.PixOffset(sx, sy):img.PixOffset(sx, sy)+4])
+		}
+	}
+	return r
+}
+
+// Renderer defines the interface for types that render images to a terminal. All encoders provided by this
+// package implement Renderer, which allows client code to select the protocol to use at runtime.
+type Renderer interface {
+	// Render renders img and writes the resulting output to w.
+	Render(w io.Writer, img image.Image) error
+}
+
+// render encodes img using encode and writes the result to w.
+func render(w io.Writer, img image.Image, encode func(image.Image) (string, error)) error {
+	s, err := encode(img)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, s)
+	return err
+}
+
+// Detect determines the graphics protocol supported by the terminal connected to rw and returns a
+// Renderer using that protocol. The kitty graphics protocol is preferred over the iTerm2 inline image
+// protocol, which is preferred over sixel. Support for the iTerm2 protocol is determined from the
+// environment variables TERM_PROGRAM and LC_TERMINAL. If no protocol is supported, Detect returns a Blocks
+// renderer using the colors supported by profile (at least the 16 standard colors), such as the profile of
+// the terminal.Terminal connected to rw. The Blocks renderer uses REP if the terminal supports rectangular
+// editing.
+func Detect(rw io.ReadWriter, profile sgr.ColorProfile) (Renderer, error) {
+	kitty, err := IsKittySupported(rw)
+	if err != nil {
+		return nil, err
+	}
+	if kitty {
+		return Kitty{}, nil
+	}
+
+	if isITerm2Supported() {
+		return ITerm2{}, nil
+	}
+
+	da, err := csi.GetDeviceAttributes(rw)
+	if err != nil {
+		return nil, err
+	}
+	if da.Has(csi.DeviceAttributeSixel) {
+		return Sixel{}, nil
+	}
+
+	blocks := Blocks{
+		Colors: Colors16,
+		Repeat: da.Has(csi.DeviceAttributeRectangularEditing),
+	}
+
+	switch profile {
+	case sgr.ProfileTrueColor:
+		blocks.Colors = TrueColor
+	case sgr.ProfileANSI256:
+		blocks.Colors = Colors256
+	}
+
+	return blocks, nil
+}
+
+// isITerm2Supported returns whether the environment signals a terminal supporting the iTerm2 inline image
+// protocol.
+func isITerm2Supported() bool {
+	switch os.Getenv("TERM_PROGRAM") {
+	case "iTerm.app", "WezTerm", "mintty":
+		return true
+	}
+
+	return os.Getenv("LC_TERMINAL") == "iTerm2"
+}
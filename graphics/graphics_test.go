@@ -0,0 +1,113 @@
+package graphics
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/halimath/expect"
+	"github.com/halimath/expect/is"
+	"github.com/halimath/terminal/sgr"
+)
+
+// testImage creates a w x h image filled with c.
+func testImage(w, h int, c color.Color) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+type rw struct {
+	r bytes.Buffer
+	w bytes.Buffer
+
+	// queue contains responses that are moved to r one at a time once r has been consumed. Use it to
+	// simulate responses to multiple queries.
+	queue []string
+}
+
+func (rw *rw) Read(p []byte) (n int, err error) {
+	if rw.r.Len() == 0 && len(rw.queue) > 0 {
+		rw.r.WriteString(rw.queue[0])
+		rw.queue = rw.queue[1:]
+	}
+	return rw.r.Read(p)
+}
+
+func (rw *rw) Write(p []byte) (n int, err error) {
+	return rw.w.Write(p)
+}
+
+func TestDetect(t *testing.T) {
+	t.Setenv("TERM_PROGRAM", "")
+	t.Setenv("LC_TERMINAL", "")
+
+	t.Run("kitty", func(t *testing.T) {
+		var rw rw
+		rw.r.WriteString("\x1b_Gi=31;OK\x1b\\\x1b[?62;4c")
+
+		got, err := Detect(&rw, sgr.ProfileANSI256)
+		expect.That(t,
+			is.NoError(err),
+			is.DeepEqualTo(got, Renderer(Kitty{})),
+		)
+	})
+
+	t.Run("iterm2", func(t *testing.T) {
+		t.Setenv("TERM_PROGRAM", "WezTerm")
+
+		var rw rw
+		rw.r.WriteString("\x1b[?62;4c")
+
+		got, err := Detect(&rw, sgr.ProfileANSI256)
+		expect.That(t,
+			is.NoError(err),
+			is.DeepEqualTo(got, Renderer(ITerm2{})),
+		)
+	})
+
+	t.Run("sixel", func(t *testing.T) {
+		rw := rw{queue: []string{"\x1b[?62;4c", "\x1b[?62;4c"}}
+
+		got, err := Detect(&rw, sgr.ProfileANSI256)
+		expect.That(t,
+			is.NoError(err),
+			is.DeepEqualTo(got, Renderer(Sixel{})),
+		)
+	})
+
+	t.Run("blocks", func(t *testing.T) {
+		rw := rw{queue: []string{"\x1b[?64;22;28c", "\x1b[?64;22;28c"}}
+
+		got, err := Detect(&rw, sgr.ProfileANSI256)
+		expect.That(t,
+			is.NoError(err),
+			is.DeepEqualTo(got, Renderer(Blocks{Colors: Colors256, Repeat: true})),
+		)
+	})
+
+	t.Run("blocksTrueColor", func(t *testing.T) {
+		rw := rw{queue: []string{"\x1b[?64;22c", "\x1b[?64;22c"}}
+
+		got, err := Detect(&rw, sgr.ProfileTrueColor)
+		expect.That(t,
+			is.NoError(err),
+			is.DeepEqualTo(got, Renderer(Blocks{Colors: TrueColor})),
+		)
+	})
+
+	t.Run("blocks16", func(t *testing.T) {
+		rw := rw{queue: []string{"\x1b[?64;22c", "\x1b[?64;22c"}}
+
+		got, err := Detect(&rw, sgr.ProfileANSI)
+		expect.That(t,
+			is.NoError(err),
+			is.DeepEqualTo(got, Renderer(Blocks{Colors: Colors16})),
+		)
+	})
+}
@@ -0,0 +1,102 @@
+package graphics
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/halimath/terminal/csi"
+)
+
+// Dimension defines the width or height of an image displayed using the iTerm2 inline image protocol.
+type Dimension string
+
+// Auto lets the terminal determine a dimension based on the image's size.
+const Auto Dimension = "auto"
+
+// Cells creates a Dimension of n character cells.
+func Cells(n int) Dimension {
+	return Dimension(strconv.Itoa(n))
+}
+
+// Pixels creates a Dimension of n pixels.
+func Pixels(n int) Dimension {
+	return Dimension(strconv.Itoa(n) + "px")
+}
+
+// Percent creates a Dimension of n percent of the terminal's width or height.
+func Percent(n int) Dimension {
+	return Dimension(strconv.Itoa(n) + "%")
+}
+
+// ITerm2 encodes images and files using the inline image protocol (OSC 1337 File) defined by iTerm2 and
+// supported by WezTerm, mintty and others. The zero value is a valid encoder that displays images using
+// their native size.
+//
+// See https://iterm2.com/documentation-images.html for the protocol's specification.
+type ITerm2 struct {
+	// Width and Height define the size of the displayed image. An empty value is equivalent to Auto.
+	Width, Height Dimension
+
+	// IgnoreAspectRatio allows the terminal to stretch the image to fill both Width and Height. By default,
+	// the image's aspect ratio is preserved.
+	IgnoreAspectRatio bool
+}
+
+// Encode encodes img as PNG into an OSC 1337 sequence that displays img inline.
+func (i ITerm2) Encode(img image.Image) (string, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return "", err
+	}
+
+	return i.EncodeFile("", buf.Bytes(), true), nil
+}
+
+// EncodeFile encodes the contents of a file into an OSC 1337 sequence. If inline is true, the file is
+// displayed inline (which requires data to contain an image format supported by the terminal). Otherwise,
+// the terminal offers the file for download using name as the file's name. name may be empty.
+func (i ITerm2) EncodeFile(name string, data []byte, inline bool) string {
+	args := make([]string, 0, 6)
+
+	if name != "" {
+		args = append(args, "name="+base64.StdEncoding.EncodeToString([]byte(name)))
+	}
+
+	args = append(args, fmt.Sprintf("size=%d", len(data)))
+
+	if i.Width != "" {
+		args = append(args, "width="+sanitizeDimension(i.Width))
+	}
+	if i.Height != "" {
+		args = append(args, "height="+sanitizeDimension(i.Height))
+	}
+	if i.IgnoreAspectRatio {
+		args = append(args, "preserveAspectRatio=0")
+	}
+	if inline {
+		args = append(args, "inline=1")
+	}
+
+	return fmt.Sprintf("%s1337;File=%s:%s%s", csi.OSC, strings.Join(args, ";"), base64.StdEncoding.EncodeToString(data), csi.StringTerminator)
+}
+
+// Render implements Renderer. It works like Encode and writes the result to w.
+func (i ITerm2) Render(w io.Writer, img image.Image) error {
+	return render(w, img, i.Encode)
+}
+
+// sanitizeDimension removes all characters from d that are not valid within a dimension.
+func sanitizeDimension(d Dimension) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= '0' && r <= '9') || (r >= 'a' && r <= 'z') || r == '%' {
+			return r
+		}
+		return -1
+	}, string(d))
+}
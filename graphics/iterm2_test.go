@@ -0,0 +1,33 @@
+package graphics
+
+import (
+	"bytes"
+	"image/color"
+	"strings"
+	"testing"
+
+	"github.com/halimath/expect"
+	"github.com/halimath/expect/is"
+)
+
+func TestITerm2_EncodeFile(t *testing.T) {
+	expect.That(t,
+		is.EqualTo(ITerm2{}.EncodeFile("a.txt", []byte("hello"), false), "\x1b]1337;File=name=YS50eHQ=;size=5:aGVsbG8=\x1b\\"),
+		is.EqualTo(ITerm2{Width: Cells(10), Height: Percent(50), IgnoreAspectRatio: true}.EncodeFile("", []byte("hello"), true),
+			"\x1b]1337;File=size=5;width=10;height=50%;preserveAspectRatio=0;inline=1:aGVsbG8=\x1b\\"),
+		is.EqualTo(ITerm2{Width: Pixels(100), Height: Auto}.EncodeFile("", nil, true),
+			"\x1b]1337;File=size=0;width=100px;height=auto;inline=1:\x1b\\"),
+	)
+}
+
+func TestITerm2_Render(t *testing.T) {
+	var buf bytes.Buffer
+	var r Renderer = ITerm2{}
+
+	err := r.Render(&buf, testImage(1, 1, color.White))
+	expect.That(t,
+		is.NoError(err),
+		is.EqualTo(strings.HasPrefix(buf.String(), "\x1b]1337;File=size="), true),
+		is.EqualTo(strings.Contains(buf.String(), ";inline=1:iVBORw0KGgo"), true),
+	)
+}
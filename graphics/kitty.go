@@ -0,0 +1,265 @@
+package graphics
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/halimath/terminal/csi"
+)
+
+// KittyFormat defines the format used to transmit pixel data with the kitty graphics protocol.
+type KittyFormat int
+
+const (
+	KittyFormatRGBA KittyFormat = 32  // Raw, non-premultiplied 8 bit RGBA pixel data
+	KittyFormatPNG  KittyFormat = 100 // PNG encoded data
+)
+
+// kittyChunkSize is the maximum size of a single chunk of base64 encoded payload.
+const kittyChunkSize = 4096
+
+// Kitty encodes images using the kitty graphics protocol. The zero value is a valid encoder that transmits
+// RGBA data and places the image at the cursor position using its native size.
+//
+// See https://sw.kovidgoyal.net/kitty/graphics-protocol/ for the protocol's specification.
+type Kitty struct {
+	// ID is the image's ID used to refer to the image in later commands (i.e. deletion). An ID of 0 lets
+	// the terminal choose an ID.
+	ID uint32
+
+	// PlacementID identifies the placement of the image. Multiple placements of the same image can be
+	// displayed. An ID of 0 lets the terminal choose an ID.
+	PlacementID uint32
+
+	// Format defines the format to transmit the pixel data. Defaults to KittyFormatRGBA.
+	Format KittyFormat
+
+	// Compress defines whether RGBA data should be compressed using zlib before transmitting.
+	Compress bool
+
+	// Columns and Rows define the number of cells to display the image in. The image is scaled to fit.
+	// If either is 0, the terminal computes the value from the image's size.
+	Columns, Rows int
+
+	// ZIndex defines the vertical stacking order of the image. Negative values place the image below text.
+	ZIndex int32
+
+	// DoNotMoveCursor prevents the terminal from moving the cursor after placing the image.
+	DoNotMoveCursor bool
+}
+
+// Encode encodes img into a sequence of APC commands that transmit and display img. The terminal is
+// instructed to suppress all responses, so the result can be written to the terminal without reading
+// anything back. Use Display to receive the terminal's response.
+func (k Kitty) Encode(img image.Image) (string, error) {
+	return k.encode(img, 2)
+}
+
+// Display transmits and displays img on the terminal connected to rw. If k.ID is set, the terminal is
+// instructed to report success as well as failure; the response is read and returned as an error in case
+// the terminal reports one (see KittyError). If k.ID is 0, no response is requested and none is read.
+func (k Kitty) Display(rw io.ReadWriter, img image.Image) error {
+	if k.ID == 0 {
+		s, err := k.Encode(img)
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(rw, s)
+		return err
+	}
+
+	// q=0 requests the OK response, too. With q=1, the terminal only reports errors and reading the
+	// response would block forever on success.
+	s, err := k.encode(img, 0)
+	if err != nil {
+		return err
+	}
+
+	_, err = csi.QueryString(rw, s, 512, func(res []byte) (struct{}, error) {
+		_, err := ParseKittyResponse(res)
+		return struct{}{}, err
+	})
+	return err
+}
+
+// Render implements Renderer. It works like Encode and writes the result to w.
+func (k Kitty) Render(w io.Writer, img image.Image) error {
+	return render(w, img, k.Encode)
+}
+
+func (k Kitty) encode(img image.Image, quiet int) (string, error) {
+	var payload []byte
+
+	keys := []string{"a=T", "q=" + strconv.Itoa(quiet)}
+
+	switch k.Format {
+	case KittyFormatPNG:
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, img); err != nil {
+			return "", err
+		}
+		payload = buf.Bytes()
+		keys = append(keys, "f=100")
+
+	case 0, KittyFormatRGBA:
+		n := toNRGBA(img)
+		payload = n.Pix
+		keys = append(keys, "f=32", fmt.Sprintf("s=%d", n.Rect.Dx()), fmt.Sprintf("v=%d", n.Rect.Dy()))
+
+		if k.Compress {
+			var buf bytes.Buffer
+			w := zlib.NewWriter(&buf)
+			if _, err := w.Write(payload); err != nil {
+				return "", err
+			}
+			if err := w.Close(); err != nil {
+				return "", err
+			}
+			payload = buf.Bytes()
+			keys = append(keys, "o=z")
+		}
+
+	default:
+		return "", fmt.Errorf("unsupported kitty format: %d", k.Format)
+	}
+
+	if k.ID != 0 {
+		keys = append(keys, fmt.Sprintf("i=%d", k.ID))
+	}
+	if k.PlacementID != 0 {
+		keys = append(keys, fmt.Sprintf("p=%d", k.PlacementID))
+	}
+	if k.Columns > 0 {
+		keys = append(keys, fmt.Sprintf("c=%d", k.Columns))
+	}
+	if k.Rows > 0 {
+		keys = append(keys, fmt.Sprintf("r=%d", k.Rows))
+	}
+	if k.ZIndex != 0 {
+		keys = append(keys, fmt.Sprintf("z=%d", k.ZIndex))
+	}
+	if k.DoNotMoveCursor {
+		keys = append(keys, "C=1")
+	}
+
+	return encodeKittyChunks(strings.Join(keys, ","), quiet, base64.StdEncoding.EncodeToString(payload)), nil
+}
+
+// encodeKittyChunks splits the base64 encoded payload into chunks and creates an APC command for each
+// chunk. The first command carries keys; subsequent commands only carry the chunking information.
+func encodeKittyChunks(keys string, quiet int, payload string) string {
+	var b strings.Builder
+
+	first := true
+	for {
+		chunk := payload
+		if len(chunk) > kittyChunkSize {
+			chunk = chunk[:kittyChunkSize]
+		}
+		payload = payload[len(chunk):]
+
+		more := 0
+		if len(payload) > 0 {
+			more = 1
+		}
+
+		if first {
+			fmt.Fprintf(&b, "%sG%s,m=%d;%s%s", csi.APC, keys, more, chunk, csi.StringTerminator)
+			first = false
+		} else {
+			fmt.Fprintf(&b, "%sGq=%d,m=%d;%s%s", csi.APC, quiet, more, chunk, csi.StringTerminator)
+		}
+
+		if more == 0 {
+			return b.String()
+		}
+	}
+}
+
+// KittyDeleteAll deletes all images visible on screen and frees their data.
+const KittyDeleteAll = csi.APC + "Ga=d,d=A,q=2" + csi.StringTerminator
+
+// KittyDeleteImage creates a command that deletes all placements of the image with the given ID and frees
+// the image's data.
+func KittyDeleteImage(id uint32) string {
+	return fmt.Sprintf("%sGa=d,d=I,i=%d,q=2%s", csi.APC, id, csi.StringTerminator)
+}
+
+// KittyDeletePlacement creates a command that deletes the placement placementID of the image id. The
+// image's data is kept to allow creating new placements.
+func KittyDeletePlacement(id, placementID uint32) string {
+	return fmt.Sprintf("%sGa=d,d=i,i=%d,p=%d,q=2%s", csi.APC, id, placementID, csi.StringTerminator)
+}
+
+// KittyError is returned when the terminal reports an error in response to a kitty graphics command.
+type KittyError struct {
+	// ID of the image the error refers to.
+	ID uint32
+	// Code is the error code, such as ENOENT or EINVAL.
+	Code string
+	// Message is the human readable error message.
+	Message string
+}
+
+func (e *KittyError) Error() string {
+	return fmt.Sprintf("kitty graphics error for image %d: %s: %s", e.ID, e.Code, e.Message)
+}
+
+// ParseKittyResponse parses res which must be a kitty graphics response of the form
+//
+//	APC G <keys> ; <message> ST
+//
+// and returns the ID of the image the response refers to. If the message is not "OK", a *KittyError is
+// returned.
+func ParseKittyResponse(res []byte) (uint32, error) {
+	if !bytes.HasPrefix(res, []byte(csi.APC+"G")) || !bytes.HasSuffix(res, []byte(csi.StringTerminator)) {
+		return 0, fmt.Errorf("%w: kitty graphics: %q", csi.ErrInvalidTerminalResponse, res)
+	}
+
+	keys, msg, ok := strings.Cut(string(res[len(csi.APC)+1:len(res)-len(csi.StringTerminator)]), ";")
+	if !ok {
+		return 0, fmt.Errorf("%w: kitty graphics: %q", csi.ErrInvalidTerminalResponse, res)
+	}
+
+	var id uint32
+	for _, kv := range strings.Split(keys, ",") {
+		if v, ok := strings.CutPrefix(kv, "i="); ok {
+			i, err := strconv.ParseUint(v, 10, 32)
+			if err != nil {
+				return 0, fmt.Errorf("%w: kitty graphics: %q", csi.ErrInvalidTerminalResponse, res)
+			}
+			id = uint32(i)
+		}
+	}
+
+	if msg == "OK" {
+		return id, nil
+	}
+
+	code, message, _ := strings.Cut(msg, ":")
+	return id, &KittyError{ID: id, Code: code, Message: message}
+}
+
+// kittySupportQuery transmits a 1x1 pixel image without displaying it. Terminals supporting the kitty
+// graphics protocol answer with an OK response.
+const kittySupportQuery = csi.APC + "Gi=31,s=1,v=1,a=q,t=d,f=24;AAAA" + csi.StringTerminator
+
+// IsKittySupported queries whether the terminal connected to rw supports the kitty graphics protocol. The
+// query is guarded so this function does not block on terminals ignoring the query.
+func IsKittySupported(rw io.ReadWriter) (bool, error) {
+	return csi.QueryGuarded(rw, kittySupportQuery, 256, func(res []byte) (bool, error) {
+		if len(res) == 0 {
+			return false, nil
+		}
+
+		_, err := ParseKittyResponse(res)
+		return err == nil, nil
+	})
+}
@@ -0,0 +1,102 @@
+package graphics
+
+import (
+	"image/color"
+	"strings"
+	"testing"
+
+	"github.com/halimath/expect"
+	"github.com/halimath/expect/is"
+)
+
+func TestKitty_Encode(t *testing.T) {
+	t.Run("rgba", func(t *testing.T) {
+		got, err := Kitty{ID: 7, Columns: 2, Rows: 1, ZIndex: -1}.Encode(testImage(1, 1, color.NRGBA{R: 255, A: 255}))
+		expect.That(t,
+			is.NoError(err),
+			is.EqualTo(got, "\x1b_Ga=T,q=2,f=32,s=1,v=1,i=7,c=2,r=1,z=-1,m=0;/wAA/w==\x1b\\"),
+		)
+	})
+
+	t.Run("chunked", func(t *testing.T) {
+		got, err := Kitty{}.Encode(testImage(64, 64, color.White))
+		expect.That(t,
+			is.NoError(err),
+			is.EqualTo(strings.Count(got, "\x1b_G"), 6),
+			is.EqualTo(strings.Count(got, "m=1;"), 5),
+			is.EqualTo(strings.HasPrefix(got, "\x1b_Ga=T,q=2,f=32,s=64,v=64,m=1;"), true),
+			is.EqualTo(strings.Contains(got, "\x1b_Gq=2,m=0;"), true),
+		)
+	})
+
+	t.Run("png", func(t *testing.T) {
+		got, err := Kitty{Format: KittyFormatPNG}.Encode(testImage(1, 1, color.White))
+		expect.That(t,
+			is.NoError(err),
+			is.EqualTo(strings.HasPrefix(got, "\x1b_Ga=T,q=2,f=100,m=0;iVBORw0KGgo"), true),
+		)
+	})
+}
+
+func TestKitty_Display(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		var rw rw
+		rw.r.WriteString("\x1b_Gi=7;OK\x1b\\")
+
+		err := Kitty{ID: 7}.Display(&rw, testImage(1, 1, color.White))
+		expect.That(t,
+			is.NoError(err),
+			// q=0 is required for the terminal to send the OK response read above.
+			is.EqualTo(rw.w.String(), "\x1b_Ga=T,q=0,f=32,s=1,v=1,i=7,m=0;/////w==\x1b\\"),
+		)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		var rw rw
+		rw.r.WriteString("\x1b_Gi=7;EINVAL:bad image\x1b\\")
+
+		err := Kitty{ID: 7}.Display(&rw, testImage(1, 1, color.White))
+		expect.That(t,
+			is.DeepEqualTo(err, error(&KittyError{ID: 7, Code: "EINVAL", Message: "bad image"})),
+		)
+	})
+}
+
+func TestKittyDelete(t *testing.T) {
+	expect.That(t,
+		is.EqualTo(KittyDeleteImage(3), "\x1b_Ga=d,d=I,i=3,q=2\x1b\\"),
+		is.EqualTo(KittyDeletePlacement(3, 4), "\x1b_Ga=d,d=i,i=3,p=4,q=2\x1b\\"),
+	)
+}
+
+func TestParseKittyResponse(t *testing.T) {
+	id, err := ParseKittyResponse([]byte("\x1b_Gi=3;ENOENT:no such image\x1b\\"))
+	expect.That(t,
+		is.EqualTo(id, uint32(3)),
+		is.DeepEqualTo(err, error(&KittyError{ID: 3, Code: "ENOENT", Message: "no such image"})),
+	)
+}
+
+func TestIsKittySupported(t *testing.T) {
+	t.Run("supported", func(t *testing.T) {
+		var rw rw
+		rw.r.WriteString("\x1b_Gi=31;OK\x1b\\\x1b[?62;c")
+
+		got, err := IsKittySupported(&rw)
+		expect.That(t,
+			is.NoError(err),
+			is.EqualTo(got, true),
+		)
+	})
+
+	t.Run("notSupported", func(t *testing.T) {
+		var rw rw
+		rw.r.WriteString("\x1b[?62;c")
+
+		got, err := IsKittySupported(&rw)
+		expect.That(t,
+			is.NoError(err),
+			is.EqualTo(got, false),
+		)
+	})
+}
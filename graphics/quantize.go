@@ -0,0 +1,176 @@
+package graphics
+
+import (
+	"image"
+	"sort"
+)
+
+// rgb is a color without alpha channel used during quantization.
+type rgb [3]uint8
+
+// colorCount is a color together with the number of pixels using it.
+type colorCount struct {
+	c rgb
+	n int
+}
+
+// alphaThreshold defines the alpha value below which a pixel is considered transparent.
+const alphaThreshold = 128
+
+// quantize reduces the colors used by img to at most maxColors using the median cut algorithm. It returns
+// the palette and, for every pixel of img, the index of the palette entry to use for that pixel. Transparent
+// pixels are assigned the index -1.
+func quantize(img *image.NRGBA, maxColors int) (palette []rgb, indices []int) {
+	w, h := img.Rect.Dx(), img.Rect.Dy()
+
+	hist := make(map[rgb]int)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			p := img.Pix[img.PixOffset(x, y):]
+			if p[3] < alphaThreshold {
+				continue
+			}
+			hist[rgb{p[0], p[1], p[2]}]++
+		}
+	}
+
+	palette = medianCut(hist, maxColors)
+
+	indices = make([]int, w*h)
+	cache := make(map[rgb]int, len(hist))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			p := img.Pix[img.PixOffset(x, y):]
+			if p[3] < alphaThreshold {
+				indices[y*w+x] = -1
+				continue
+			}
+
+			c := rgb{p[0], p[1], p[2]}
+			idx, ok := cache[c]
+			if !ok {
+				idx = nearestColor(palette, c)
+				cache[c] = idx
+			}
+			indices[y*w+x] = idx
+		}
+	}
+
+	return
+}
+
+// medianCut computes a palette of at most maxColors colors representing the colors in hist.
+func medianCut(hist map[rgb]int, maxColors int) []rgb {
+	colors := make([]colorCount, 0, len(hist))
+	for c, n := range hist {
+		colors = append(colors, colorCount{c, n})
+	}
+	// Sort to make the result independent of map iteration order.
+	sort.Slice(colors, func(i, j int) bool {
+		a, b := colors[i].c, colors[j].c
+		if a[0] != b[0] {
+			return a[0] < b[0]
+		}
+		if a[1] != b[1] {
+			return a[1] < b[1]
+		}
+		return a[2] < b[2]
+	})
+
+	if len(colors) <= maxColors {
+		palette := make([]rgb, len(colors))
+		for i, c := range colors {
+			palette[i] = c.c
+		}
+		return palette
+	}
+
+	boxes := [][]colorCount{colors}
+	for len(boxes) < maxColors {
+		// Find the box with the largest extent along any channel.
+		best, bestChannel, bestRange := -1, 0, 0
+		for i, b := range boxes {
+			if len(b) < 2 {
+				continue
+			}
+			ch, r := widestChannel(b)
+			if r > bestRange {
+				best, bestChannel, bestRange = i, ch, r
+			}
+		}
+
+		if best < 0 {
+			break
+		}
+
+		box := boxes[best]
+		sort.SliceStable(box, func(i, j int) bool { return box[i].c[bestChannel] < box[j].c[bestChannel] })
+
+		var total int
+		for _, c := range box {
+			total += c.n
+		}
+
+		// Split at the weighted median, but keep both halves non-empty.
+		split, sum := 1, 0
+		for i, c := range box[:len(box)-1] {
+			sum += c.n
+			if sum*2 >= total {
+				split = i + 1
+				break
+			}
+		}
+
+		boxes[best] = box[:split]
+		boxes = append(boxes, box[split:])
+	}
+
+	palette := make([]rgb, len(boxes))
+	for i, b := range boxes {
+		var r, g, bl, n int
+		for _, c := range b {
+			r += int(c.c[0]) * c.n
+			g += int(c.c[1]) * c.n
+			bl += int(c.c[2]) * c.n
+			n += c.n
+		}
+		palette[i] = rgb{uint8(r / n), uint8(g / n), uint8(bl / n)}
+	}
+
+	return palette
+}
+
+// widestChannel returns the channel with the largest value range in colors as well as that range.
+func widestChannel(colors []colorCount) (channel, rng int) {
+	for ch := 0; ch < 3; ch++ {
+		lo, hi := 255, 0
+		for _, c := range colors {
+			v := int(c.c[ch])
+			if v < lo {
+				lo = v
+			}
+			if v > hi {
+				hi = v
+			}
+		}
+		if hi-lo > rng {
+			channel, rng = ch, hi-lo
+		}
+	}
+	return
+}
+
+// nearestColor returns the index of the color in palette closest to c using the euclidean distance.
+func nearestColor(palette []rgb, c rgb) int {
+	best, bestDist := 0, -1
+	for i, p := range palette {
+		dr := int(p[0]) - int(c[0])
+		dg := int(p[1]) - int(c[1])
+		db := int(p[2]) - int(c[2])
+		d := dr*dr + dg*dg + db*db
+		if bestDist < 0 || d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+	return best
+}
@@ -0,0 +1,196 @@
+package graphics
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"strings"
+
+	"github.com/halimath/terminal/csi"
+)
+
+// sixelMaxColors is the maximum number of color registers supported by most sixel implementations.
+const sixelMaxColors = 256
+
+// ErrMissingCellSize is a sentinel error value returned when an image should be scaled to a number of
+// rows or columns but the size of a cell in pixels is unknown.
+var ErrMissingCellSize = errors.New("missing cell size")
+
+// Sixel encodes images using the DEC sixel graphics format. The zero value is a valid encoder that uses up
+// to 256 colors and displays the image using its native size.
+//
+// Before encoding, the image's colors are quantized to a palette using the median cut algorithm.
+// Pixels with an alpha value below 50% are rendered transparent.
+type Sixel struct {
+	// Colors is the maximum number of colors to use. Values <= 0 or greater than 256 are treated as 256.
+	Colors int
+
+	// Rows and Columns, if > 0, scale the image (preserving its aspect ratio) to occupy at most the given
+	// number of rows and columns. Scaling requires CellWidth and CellHeight to be set.
+	Rows, Columns int
+
+	// CellWidth and CellHeight define the size of a single cell in pixels. Use csi.GetCellPixelSize to
+	// query these values from the terminal.
+	CellWidth, CellHeight int
+}
+
+// Encode encodes img into a DCS sixel sequence.
+func (s Sixel) Encode(img image.Image) (string, error) {
+	n := toNRGBA(img)
+
+	w, h, err := s.size(n.Rect.Dx(), n.Rect.Dy())
+	if err != nil {
+		return "", err
+	}
+	n = resize(n, w, h)
+
+	maxColors := s.Colors
+	if maxColors <= 0 || maxColors > sixelMaxColors {
+		maxColors = sixelMaxColors
+	}
+
+	palette, indices := quantize(n, maxColors)
+
+	var b strings.Builder
+
+	// P2=1 leaves pixels without a color at their current value, which makes them transparent.
+	fmt.Fprintf(&b, "%s0;1;0q\"1;1;%d;%d", csi.DCS, w, h)
+
+	for i, c := range palette {
+		fmt.Fprintf(&b, "#%d;2;%d;%d;%d", i, sixelPercent(c[0]), sixelPercent(c[1]), sixelPercent(c[2]))
+	}
+
+	bands := make(map[int][]byte, len(palette))
+	var used []int
+
+	for y0 := 0; y0 < h; y0 += 6 {
+		if y0 > 0 {
+			// Move to the next band. No DECGNL follows the last band so the cursor does not move past the
+			// image.
+			b.WriteByte('-')
+		}
+
+		used = used[:0]
+		for k := range bands {
+			delete(bands, k)
+		}
+
+		for y := y0; y < y0+6 && y < h; y++ {
+			bit := byte(1 << (y - y0))
+			for x := 0; x < w; x++ {
+				idx := indices[y*w+x]
+				if idx < 0 {
+					continue
+				}
+
+				band, ok := bands[idx]
+				if !ok {
+					band = make([]byte, w)
+					bands[idx] = band
+					used = append(used, idx)
+				}
+				band[x] |= bit
+			}
+		}
+
+		for i, idx := range used {
+			if i > 0 {
+				// Return to the beginning of the band to overprint with the next color.
+				b.WriteByte('$')
+			}
+			fmt.Fprintf(&b, "#%d", idx)
+			writeSixels(&b, bands[idx])
+		}
+	}
+
+	b.WriteString(csi.StringTerminator)
+
+	return b.String(), nil
+}
+
+// Render implements Renderer. It works like Encode and writes the result to w.
+func (s Sixel) Render(w io.Writer, img image.Image) error {
+	return render(w, img, s.Encode)
+}
+
+// size computes the size in pixels to render an image of size w x h.
+func (s Sixel) size(w, h int) (int, int, error) {
+	if s.Rows <= 0 && s.Columns <= 0 {
+		return w, h, nil
+	}
+
+	if (s.Rows > 0 && s.CellHeight <= 0) || (s.Columns > 0 && s.CellWidth <= 0) {
+		return 0, 0, ErrMissingCellSize
+	}
+
+	w, h = fitSize(w, h, s.Columns*s.CellWidth, s.Rows*s.CellHeight)
+	return w, h, nil
+}
+
+// fitSize scales w x h preserving the aspect ratio to fit into maxW x maxH. A value <= 0 for either maximum
+// means that dimension is not constrained.
+func fitSize(w, h, maxW, maxH int) (int, int) {
+	if w == 0 || h == 0 {
+		return w, h
+	}
+
+	nw, nh := w, h
+	if maxH > 0 {
+		nw, nh = w*maxH/h, maxH
+	}
+	if maxW > 0 && (nw > maxW || maxH <= 0) {
+		nw, nh = maxW, h*maxW/w
+	}
+
+	if nw < 1 {
+		nw = 1
+	}
+	if nh < 1 {
+		nh = 1
+	}
+
+	return nw, nh
+}
+
+// writeSixels writes the sixels in band using run length encoding. Trailing empty sixels are omitted.
+func writeSixels(b *strings.Builder, band []byte) {
+	end := len(band)
+	for end > 0 && band[end-1] == 0 {
+		end--
+	}
+
+	for i := 0; i < end; {
+		j := i + 1
+		for j < end && band[j] == band[i] {
+			j++
+		}
+
+		c := band[i] + '?'
+		if run := j - i; run > 3 {
+			fmt.Fprintf(b, "!%d%c", run, c)
+		} else {
+			for k := 0; k < run; k++ {
+				b.WriteByte(c)
+			}
+		}
+
+		i = j
+	}
+}
+
+// sixelPercent converts an 8 bit color component to the percent value used by sixel color registers.
+func sixelPercent(v uint8) int {
+	return (int(v)*100 + 127) / 255
+}
+
+// IsSixelSupported queries whether the terminal connected to rw supports sixel graphics by inspecting the
+// primary device attributes.
+func IsSixelSupported(rw io.ReadWriter) (bool, error) {
+	da, err := csi.GetDeviceAttributes(rw)
+	if err != nil {
+		return false, err
+	}
+
+	return da.Has(csi.DeviceAttributeSixel), nil
+}
@@ -0,0 +1,75 @@
+package graphics
+
+import (
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+
+	"github.com/halimath/expect"
+	"github.com/halimath/expect/is"
+)
+
+func TestSixel_Encode(t *testing.T) {
+	t.Run("singleColor", func(t *testing.T) {
+		got, err := Sixel{}.Encode(testImage(8, 6, color.NRGBA{R: 255, A: 255}))
+		expect.That(t,
+			is.NoError(err),
+			is.EqualTo(got, "\x1bP0;1;0q\"1;1;8;6#0;2;100;0;0#0!8~\x1b\\"),
+		)
+	})
+
+	t.Run("twoColors", func(t *testing.T) {
+		img := testImage(2, 2, color.NRGBA{R: 255, A: 255})
+		img.Set(1, 1, color.NRGBA{B: 255, A: 255})
+		img.Set(0, 1, color.Transparent)
+
+		got, err := Sixel{}.Encode(img)
+		expect.That(t,
+			is.NoError(err),
+			is.EqualTo(got, "\x1bP0;1;0q\"1;1;2;2#0;2;0;0;100#1;2;100;0;0#1@@$#0?A\x1b\\"),
+		)
+	})
+
+	t.Run("scaled", func(t *testing.T) {
+		got, err := Sixel{Rows: 2, CellWidth: 10, CellHeight: 20}.Encode(testImage(20, 10, color.White))
+		expect.That(t,
+			is.NoError(err),
+			is.EqualTo(strings.HasPrefix(got, "\x1bP0;1;0q\"1;1;80;40"), true),
+			is.EqualTo(strings.Count(got, "-"), 6),
+			is.EqualTo(strings.HasSuffix(got, "-\x1b\\"), false),
+		)
+	})
+
+	t.Run("missingCellSize", func(t *testing.T) {
+		_, err := Sixel{Rows: 2}.Encode(testImage(20, 10, color.White))
+		expect.That(t, is.Error(err, ErrMissingCellSize))
+	})
+}
+
+func TestQuantize(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 4, 1))
+	img.Set(0, 0, color.NRGBA{R: 250, A: 255})
+	img.Set(1, 0, color.NRGBA{R: 255, A: 255})
+	img.Set(2, 0, color.NRGBA{B: 250, A: 255})
+	img.Set(3, 0, color.NRGBA{B: 255, A: 255})
+
+	palette, indices := quantize(img, 2)
+	expect.That(t,
+		is.EqualTo(len(palette), 2),
+		is.EqualTo(indices[0], indices[1]),
+		is.EqualTo(indices[2], indices[3]),
+		is.EqualTo(indices[0] != indices[2], true),
+	)
+}
+
+func TestIsSixelSupported(t *testing.T) {
+	var rw rw
+	rw.r.WriteString("\x1b[?62;4;22c")
+
+	got, err := IsSixelSupported(&rw)
+	expect.That(t,
+		is.NoError(err),
+		is.EqualTo(got, true),
+	)
+}
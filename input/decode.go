@@ -9,6 +9,7 @@ import (
 	"unicode/utf8"
 
 	"github.com/halimath/terminal/csi"
+	"github.com/halimath/terminal/text"
 )
 
 var ErrInvalidInputBytes = errors.New("invalid input byte sequence")
@@ -25,6 +26,10 @@ func Decode(b []byte) (Event, error) {
 		return nil, ErrInvalidInputBytes
 	}
 
+	if len(b) > 1 && b[0] != keyCodeEscape {
+		return decodeGrapheme(b)
+	}
+
 	switch len(b) {
 	case 1:
 		return decodeSingleByteKeyPress(b[0])
@@ -41,6 +46,21 @@ func Decode(b []byte) (Event, error) {
 	}
 }
 
+// decodeGrapheme decodes b which must contain a single grapheme cluster (a user perceived character).
+// Grapheme clusters consisting of a single rune are decoded as Char.
+func decodeGrapheme(b []byte) (KeyPress, error) {
+	g, rest := text.FirstGrapheme(string(b))
+	if rest != "" || b[0] < 0x20 || !utf8.ValidString(g) {
+		return nil, fmt.Errorf("%w: invalid grapheme: %#v", ErrInvalidInputBytes, b)
+	}
+
+	if utf8.RuneCountInString(g) == 1 {
+		return decodeUnicodeRune(b)
+	}
+
+	return Grapheme(g), nil
+}
+
 func decodeUnicodeRune(b []byte) (KeyPress, error) {
 	r, l := utf8.DecodeRune(b)
 	if l != len(b) {
@@ -36,6 +36,13 @@ func TestDecode(t *testing.T) {
 		{[]byte("世"), Char('世'), nil}, // Three bytes
 		{[]byte("𐍈"), Char('𐍈'), nil}, // Four bytes
 
+		// Grapheme clusters consisting of multiple runes
+		{[]byte("e\u0301"), Grapheme("e\u0301"), nil},
+		{[]byte("\U0001f469\u200d\U0001f4bb"), Grapheme("\U0001f469\u200d\U0001f4bb"), nil},
+		{[]byte("\U0001f1e9\U0001f1ea"), Grapheme("\U0001f1e9\U0001f1ea"), nil},
+		{[]byte("ab"), nil, ErrInvalidInputBytes},
+		{[]byte("\r\n"), nil, ErrInvalidInputBytes},
+
 		// Multi byte special keys in normal mode
 		{[]byte{0x1b, 0x5b, 0x41}, CursorUp, nil},
 		{[]byte{0x1b, 0x5b, 0x42}, CursorDown, nil},
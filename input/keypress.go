@@ -31,6 +31,17 @@ func (c Char) String() string {
 	return fmt.Sprintf("%c", c)
 }
 
+// Grapheme is a KeyPress that's a single user perceived character consisting of multiple runes, such as an
+// emoji ZWJ sequence, an emoji with a skin tone modifier, a flag or a character followed by combining marks.
+// Characters consisting of a single rune are reported as Char.
+type Grapheme string
+
+func (Grapheme) evt()      {}
+func (Grapheme) keyPress() {}
+func (g Grapheme) String() string {
+	return string(g)
+}
+
 // Ctrl is a KeyPress with a regular key combined with the control key.
 type Ctrl rune
 
@@ -6,6 +6,7 @@ import (
 	"io"
 
 	"github.com/halimath/terminal/csi"
+	"github.com/halimath/terminal/text"
 )
 
 const readerBufSize = 256
@@ -70,7 +71,24 @@ func (r *Reader) readFromLastReadBuffer() (Event, []byte, error) {
 	return evt, buf, err
 }
 
+// findSecondEventOffsetLastReadBuffer returns the offset of the second event contained in the last read
+// buffer or -1 if the buffer contains a single event. Text is split into grapheme clusters so that a
+// character consisting of multiple runes (such as an emoji ZWJ sequence) is reported as a single event.
+// Control characters are always reported as single events.
 func (r *Reader) findSecondEventOffsetLastReadBuffer() int {
+	if b := r.lastReadBuffer[0]; b != keyCodeEscape {
+		idx := 1
+		if b >= 0x20 && b != 0x7f {
+			g, _ := text.FirstGrapheme(string(r.lastReadBuffer))
+			idx = len(g)
+		}
+
+		if idx >= len(r.lastReadBuffer) {
+			return -1
+		}
+		return idx
+	}
+
 	idx := bytes.Index(r.lastReadBuffer[1:], []byte(csi.ESC))
 	if idx == -1 {
 		return -1
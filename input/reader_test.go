@@ -47,4 +47,28 @@ func TestReader_ReadInputEvent(t *testing.T) {
 			is.DeepEqualTo(got, want),
 		)
 	})
+
+	t.Run("text", func(t *testing.T) {
+		var buf bytes.Buffer
+		r := &Reader{Reader: &buf}
+		buf.WriteString("a\U0001f469\u200d\U0001f4bb\U0001f44d\U0001f3fd\r\x1bO\x48")
+
+		var got []Event
+		for {
+			evt, _, err := r.ReadInputEvent()
+			if err != nil {
+				break
+			}
+			got = append(got, evt)
+		}
+
+		want := []Event{
+			Char('a'),
+			Grapheme("\U0001f469\u200d\U0001f4bb"),
+			Grapheme("\U0001f44d\U0001f3fd"),
+			Return,
+			Home,
+		}
+		expect.That(t, is.DeepEqualTo(got, want))
+	})
 }
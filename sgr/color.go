@@ -0,0 +1,314 @@
+package sgr
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Color defines the interface for all colors that can be used as the foreground, background or underline
+// color of a Style. The implementations are ANSIColor, ANSI256Color and RGBColor. All implementations are
+// comparable using == and implement color.Color, which reports the color's RGB value (using the xterm
+// default palette for ANSIColor and ANSI256Color).
+type Color interface {
+	color.Color
+
+	// Fg returns the SGR to use c as the foreground color.
+	Fg() SGR
+	// Bg returns the SGR to use c as the background color.
+	Bg() SGR
+	// Underline returns the SGR to use c as the underline color.
+	Underline() SGR
+}
+
+// ANSIColor is one of the 16 standard colors. Values 0-7 are the normal and 8-15 the light (or bright)
+// variants. The actual color displayed depends on the terminal's color scheme. Values above 15 are reduced
+// to their lower four bits, i.e. ANSIColor(20) is used as ANSIColor(4).
+type ANSIColor uint8
+
+const (
+	Black ANSIColor = iota
+	Red
+	Green
+	Yellow
+	Blue
+	Magenta
+	Cyan
+	White
+	LightBlack
+	LightRed
+	LightGreen
+	LightYellow
+	LightBlue
+	LightMagenta
+	LightCyan
+	LightWhite
+)
+
+func (c ANSIColor) Fg() SGR {
+	c &= 0xf
+	if c < 8 {
+		return SGR(fmt.Sprintf("%d", 30+c))
+	}
+	return SGR(fmt.Sprintf("%d", 90+c-8))
+}
+
+func (c ANSIColor) Bg() SGR {
+	c &= 0xf
+	if c < 8 {
+		return SGR(fmt.Sprintf("%d", 40+c))
+	}
+	return SGR(fmt.Sprintf("%d", 100+c-8))
+}
+
+func (c ANSIColor) Underline() SGR {
+	return SGR(fmt.Sprintf("58;5;%d", c&0xf))
+}
+
+func (c ANSIColor) RGBA() (r, g, b, a uint32) {
+	return ANSI256Color(c & 0xf).RGBA()
+}
+
+// ANSI256Color is one of the 256 colors of the extended palette. Values 0-15 are the standard colors,
+// 16-231 form a 6x6x6 color cube and 232-255 a grayscale ramp.
+type ANSI256Color uint8
+
+func (c ANSI256Color) Fg() SGR        { return SGR(fmt.Sprintf("38;5;%d", c)) }
+func (c ANSI256Color) Bg() SGR        { return SGR(fmt.Sprintf("48;5;%d", c)) }
+func (c ANSI256Color) Underline() SGR { return SGR(fmt.Sprintf("58;5;%d", c)) }
+
+func (c ANSI256Color) RGBA() (r, g, b, a uint32) {
+	return palette256[c].RGBA()
+}
+
+// RGBColor is a true color given by its red, green and blue components.
+type RGBColor struct {
+	R, G, B uint8
+}
+
+func (c RGBColor) Fg() SGR        { return FgTrueColor(c.R, c.G, c.B) }
+func (c RGBColor) Bg() SGR        { return BgTrueColor(c.R, c.G, c.B) }
+func (c RGBColor) Underline() SGR { return SGR(fmt.Sprintf("58;2;%d;%d;%d", c.R, c.G, c.B)) }
+
+func (c RGBColor) RGBA() (r, g, b, a uint32) {
+	return color.RGBA{c.R, c.G, c.B, 0xff}.RGBA()
+}
+
+// ErrInvalidColor is a sentinel error value returned when parsing a color fails.
+var ErrInvalidColor = errors.New("invalid color")
+
+// Hex parses s as a hexadecimal color in one of the forms #rgb or #rrggbb. The leading # is optional.
+func Hex(s string) (RGBColor, error) {
+	h := strings.TrimPrefix(s, "#")
+
+	if len(h) == 3 {
+		h = string([]byte{h[0], h[0], h[1], h[1], h[2], h[2]})
+	}
+
+	if len(h) != 6 {
+		return RGBColor{}, fmt.Errorf("%w: %q", ErrInvalidColor, s)
+	}
+
+	v, err := strconv.ParseUint(h, 16, 32)
+	if err != nil {
+		return RGBColor{}, fmt.Errorf("%w: %q", ErrInvalidColor, s)
+	}
+
+	return RGBColor{uint8(v >> 16), uint8(v >> 8), uint8(v)}, nil
+}
+
+// HSL creates a color from hue h (in degrees), saturation s and lightness l (both in the range 0 to 1).
+func HSL(h, s, l float64) RGBColor {
+	h = math.Mod(h, 360)
+	if h < 0 {
+		h += 360
+	}
+	s = clamp01(s)
+	l = clamp01(l)
+
+	c := (1 - math.Abs(2*l-1)) * s
+	x := c * (1 - math.Abs(math.Mod(h/60, 2)-1))
+	m := l - c/2
+
+	var r, g, b float64
+	switch {
+	case h < 60:
+		r, g, b = c, x, 0
+	case h < 120:
+		r, g, b = x, c, 0
+	case h < 180:
+		r, g, b = 0, c, x
+	case h < 240:
+		r, g, b = 0, x, c
+	case h < 300:
+		r, g, b = x, 0, c
+	default:
+		r, g, b = c, 0, x
+	}
+
+	return RGBColor{to8Bit(r + m), to8Bit(g + m), to8Bit(b + m)}
+}
+
+// FromColor converts c into a Color. If c already is a Color, it is returned unchanged. Otherwise, c is
+// converted to an RGBColor ignoring its alpha channel.
+func FromColor(c color.Color) Color {
+	if sc, ok := c.(Color); ok {
+		return sc
+	}
+
+	return toRGB(c)
+}
+
+// toRGB converts c to an RGBColor ignoring its alpha channel.
+func toRGB(c color.Color) RGBColor {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return RGBColor{n.R, n.G, n.B}
+}
+
+func clamp01(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
+
+func to8Bit(v float64) uint8 {
+	return uint8(math.Round(clamp01(v) * 255))
+}
+
+// ColorProfile defines the colors supported by a terminal. Profiles are ordered, so a profile supports all
+// colors of the profiles less than itself.
+type ColorProfile int
+
+const (
+	ProfileNone      ColorProfile = iota // No colors at all
+	ProfileANSI                          // The 16 standard colors
+	ProfileANSI256                       // The 256 colors palette
+	ProfileTrueColor                     // 24 bit RGB colors
+)
+
+// Convert degrades c to the nearest color supported by p. Colors are compared perceptually using their
+// distance in the CIELAB color space (ΔE). For ProfileNone, nil is returned.
+func (p ColorProfile) Convert(c Color) Color {
+	if c == nil {
+		return nil
+	}
+
+	switch p {
+	case ProfileNone:
+		return nil
+
+	case ProfileANSI:
+		if a, ok := c.(ANSIColor); ok {
+			return a
+		}
+		return ANSIColor(nearestPaletteColor(c, 0, 16))
+
+	case ProfileANSI256:
+		switch c.(type) {
+		case ANSIColor, ANSI256Color:
+			return c
+		}
+		// The standard colors are skipped as their actual values depend on the terminal's color scheme.
+		return ANSI256Color(nearestPaletteColor(c, 16, 256))
+
+	default:
+		return c
+	}
+}
+
+// ConvertStyle degrades all colors of s using Convert. Attributes are kept unchanged.
+func (p ColorProfile) ConvertStyle(s Style) Style {
+	s.Fg = p.Convert(s.Fg)
+	s.Bg = p.Convert(s.Bg)
+	s.UnderlineColor = p.Convert(s.UnderlineColor)
+	return s
+}
+
+// palette256 contains the RGB values of the 256 colors palette as used by xterm by default.
+var palette256 [256]RGBColor
+
+// paletteLab contains the CIELAB values of palette256.
+var paletteLab [256]lab
+
+func init() {
+	standard := [16]RGBColor{
+		{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0}, {0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+		{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0}, {92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+	}
+	copy(palette256[:], standard[:])
+
+	levels := [6]uint8{0, 95, 135, 175, 215, 255}
+	for i := 0; i < 216; i++ {
+		palette256[16+i] = RGBColor{levels[i/36], levels[(i/6)%6], levels[i%6]}
+	}
+
+	for i := 0; i < 24; i++ {
+		v := uint8(8 + 10*i)
+		palette256[232+i] = RGBColor{v, v, v}
+	}
+
+	for i, c := range palette256 {
+		paletteLab[i] = toLab(c)
+	}
+}
+
+// nearestPaletteColor returns the index of the color among the entries from to to (exclusive) of the 256
+// colors palette that is perceptually closest to c.
+func nearestPaletteColor(c Color, from, to int) int {
+	l := toLab(toRGB(c))
+
+	best, bestDist := from, math.Inf(1)
+	for i := from; i < to; i++ {
+		if d := l.distance(paletteLab[i]); d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+
+	return best
+}
+
+// lab is a color in the CIELAB color space.
+type lab struct {
+	l, a, b float64
+}
+
+// distance computes the color difference ΔE (CIE76) between c and o.
+func (c lab) distance(o lab) float64 {
+	dl, da, db := c.l-o.l, c.a-o.a, c.b-o.b
+	return math.Sqrt(dl*dl + da*da + db*db)
+}
+
+// toLab converts the sRGB color c to CIELAB using the D65 white point.
+func toLab(c RGBColor) lab {
+	r, g, b := linearize(c.R), linearize(c.G), linearize(c.B)
+
+	x := (0.4124564*r + 0.3575761*g + 0.1804375*b) / 0.95047
+	y := 0.2126729*r + 0.7151522*g + 0.0721750*b
+	z := (0.0193339*r + 0.1191920*g + 0.9503041*b) / 1.08883
+
+	fx, fy, fz := labF(x), labF(y), labF(z)
+
+	return lab{
+		l: 116*fy - 16,
+		a: 500 * (fx - fy),
+		b: 200 * (fy - fz),
+	}
+}
+
+// linearize converts an sRGB component to linear light.
+func linearize(v uint8) float64 {
+	c := float64(v) / 255
+	if c <= 0.04045 {
+		return c / 12.92
+	}
+	return math.Pow((c+0.055)/1.055, 2.4)
+}
+
+func labF(t float64) float64 {
+	const delta = 6.0 / 29
+	if t > delta*delta*delta {
+		return math.Cbrt(t)
+	}
+	return t/(3*delta*delta) + 4.0/29
+}
@@ -0,0 +1,129 @@
+package sgr
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/halimath/expect"
+	"github.com/halimath/expect/is"
+)
+
+func TestANSIColor(t *testing.T) {
+	expect.That(t,
+		is.EqualTo(Red.Fg(), FgRed),
+		is.EqualTo(Red.Bg(), BgRed),
+		is.EqualTo(LightCyan.Fg(), FgLightCyan),
+		is.EqualTo(LightCyan.Bg(), BgLightCyan),
+		is.EqualTo(LightCyan.Underline(), "58;5;14"),
+
+		// Values above 15 are masked like RGBA does.
+		is.EqualTo(ANSIColor(20).Fg(), Blue.Fg()),
+		is.EqualTo(ANSIColor(20).Bg(), Blue.Bg()),
+		is.EqualTo(ANSIColor(30).Fg(), LightCyan.Fg()),
+		is.EqualTo(ANSIColor(30).Underline(), LightCyan.Underline()),
+		is.EqualTo(color.RGBAModel.Convert(ANSIColor(20)), color.RGBAModel.Convert(Blue)),
+	)
+}
+
+func TestANSI256Color(t *testing.T) {
+	expect.That(t,
+		is.EqualTo(ANSI256Color(33).Fg(), FgRGB(0, 2, 5)),
+		is.EqualTo(ANSI256Color(33).Bg(), BgRGB(0, 2, 5)),
+		is.EqualTo(ANSI256Color(33).Underline(), "58;5;33"),
+	)
+}
+
+func TestRGBColor(t *testing.T) {
+	expect.That(t,
+		is.EqualTo(RGBColor{0, 128, 59}.Fg(), FgTrueColor(0, 128, 59)),
+		is.EqualTo(RGBColor{0, 128, 59}.Bg(), BgTrueColor(0, 128, 59)),
+		is.EqualTo(RGBColor{0, 128, 59}.Underline(), "58;2;0;128;59"),
+	)
+}
+
+func TestColor_RGBA(t *testing.T) {
+	expect.That(t,
+		is.EqualTo(toRGB(Red), RGBColor{205, 0, 0}),
+		is.EqualTo(toRGB(ANSI256Color(33)), RGBColor{0, 135, 255}),
+		is.EqualTo(toRGB(ANSI256Color(232)), RGBColor{8, 8, 8}),
+		is.EqualTo(toRGB(RGBColor{1, 2, 3}), RGBColor{1, 2, 3}),
+	)
+}
+
+func TestHex(t *testing.T) {
+	type testCase struct {
+		in   string
+		want RGBColor
+		err  error
+	}
+
+	tests := []testCase{
+		{"#ff8000", RGBColor{255, 128, 0}, nil},
+		{"FF8000", RGBColor{255, 128, 0}, nil},
+		{"#f80", RGBColor{255, 136, 0}, nil},
+		{"#ff80", RGBColor{}, ErrInvalidColor},
+		{"#gg8000", RGBColor{}, ErrInvalidColor},
+	}
+
+	for _, test := range tests {
+		got, err := Hex(test.in)
+		expect.WithMessage(t, "input %q", test.in).
+			That(
+				is.Error(err, test.err),
+				is.EqualTo(got, test.want),
+			)
+	}
+}
+
+func TestHSL(t *testing.T) {
+	expect.That(t,
+		is.EqualTo(HSL(0, 1, 0.5), RGBColor{255, 0, 0}),
+		is.EqualTo(HSL(120, 1, 0.5), RGBColor{0, 255, 0}),
+		is.EqualTo(HSL(-120, 1, 0.5), RGBColor{0, 0, 255}),
+		is.EqualTo(HSL(0, 0, 1), RGBColor{255, 255, 255}),
+		is.EqualTo(HSL(30, 1, 0.25), RGBColor{128, 64, 0}),
+	)
+}
+
+func TestFromColor(t *testing.T) {
+	expect.That(t,
+		is.EqualTo(FromColor(color.RGBA{255, 0, 0, 255}), Color(RGBColor{255, 0, 0})),
+		is.EqualTo(FromColor(color.Gray{128}), Color(RGBColor{128, 128, 128})),
+		is.EqualTo(FromColor(Red), Color(Red)),
+	)
+}
+
+func TestColorProfile_Convert(t *testing.T) {
+	type testCase struct {
+		profile ColorProfile
+		in      Color
+		want    Color
+	}
+
+	tests := []testCase{
+		{ProfileTrueColor, RGBColor{1, 2, 3}, RGBColor{1, 2, 3}},
+		{ProfileANSI256, RGBColor{255, 0, 0}, ANSI256Color(196)},
+		{ProfileANSI256, RGBColor{0, 135, 255}, ANSI256Color(33)},
+		{ProfileANSI256, RGBColor{128, 128, 128}, ANSI256Color(244)},
+		{ProfileANSI256, Red, Red},
+		{ProfileANSI, RGBColor{200, 10, 10}, Red},
+		{ProfileANSI, RGBColor{250, 250, 250}, LightWhite},
+		{ProfileANSI, ANSI256Color(21), Blue},
+		{ProfileANSI, Cyan, Cyan},
+		{ProfileNone, Cyan, nil},
+		{ProfileANSI, nil, nil},
+	}
+
+	for _, test := range tests {
+		expect.WithMessage(t, "%v %v", test.profile, test.in).
+			That(is.EqualTo(test.profile.Convert(test.in), test.want))
+	}
+}
+
+func TestColorProfile_ConvertStyle(t *testing.T) {
+	s := Style{Fg: RGBColor{255, 0, 0}, Bg: Blue, Attrs: AttrBold}
+	expect.That(t,
+		is.EqualTo(ProfileANSI256.ConvertStyle(s), Style{Fg: ANSI256Color(196), Bg: Blue, Attrs: AttrBold}),
+		is.EqualTo(ProfileNone.ConvertStyle(s), Style{Attrs: AttrBold}),
+	)
+}
@@ -0,0 +1,266 @@
+package sgr
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+
+	"github.com/halimath/terminal/csi"
+)
+
+// Downsample rewrites all SGRs contained in b so that they only use colors supported by p. RGB and
+// 256 colors are converted to the nearest color available (see ColorProfile.Convert). For ProfileNone all
+// color instructions are removed while other instructions (such as bold) are kept. All other bytes are
+// left unchanged.
+func Downsample(b []byte, p ColorProfile) []byte {
+	if p >= ProfileTrueColor {
+		return b
+	}
+
+	return rewriteSGRs(b, func(params string) (string, bool) {
+		return downsampleParams(params, p)
+	})
+}
+
+// rewriteSGRs applies rewrite to the parameters of all SGRs contained in b. rewrite returns the rewritten
+// parameters and whether they have been changed. All other bytes are left unchanged. An SGR with all its
+// parameters removed is dropped completely.
+func rewriteSGRs(b []byte, rewrite func(params string) (string, bool)) []byte {
+	if bytes.IndexByte(b, csi.ESC[0]) < 0 {
+		return b
+	}
+
+	var buf bytes.Buffer
+	buf.Grow(len(b))
+
+	for len(b) > 0 {
+		idx := bytes.Index(b, csiBytes)
+		if idx < 0 {
+			buf.Write(b)
+			break
+		}
+
+		buf.Write(b[:idx])
+		b = b[idx:]
+
+		n, complete := csiLength(b)
+		if !complete {
+			buf.Write(b)
+			break
+		}
+
+		buf.Write(rewriteSequence(b[:n], rewrite))
+		b = b[n:]
+	}
+
+	return buf.Bytes()
+}
+
+// csiLength returns the length of the control sequence starting at the beginning of b (which must start
+// with csi.CSI). If b does not contain the sequence's final byte, len(b) and false are returned.
+func csiLength[T string | []byte](b T) (int, bool) {
+	for i := len(csiBytes); i < len(b); i++ {
+		if b[i] >= 0x40 && b[i] <= 0x7e {
+			return i + 1, true
+		}
+		if b[i] < 0x20 || b[i] > 0x3f {
+			// Not a valid parameter or intermediate byte; treat the sequence as ending here.
+			return i, true
+		}
+	}
+	return len(b), false
+}
+
+// rewriteSequence rewrites seq using rewrite if it is an SGR. Any other sequence is returned unchanged.
+func rewriteSequence(seq []byte, rewrite func(params string) (string, bool)) []byte {
+	params, ok := sgrParams(seq)
+	if !ok {
+		return seq
+	}
+
+	rewritten, changed := rewrite(params)
+	if !changed {
+		return seq
+	}
+	if rewritten == "" {
+		// All instructions have been removed. An empty SGR would reset all attributes.
+		return nil
+	}
+
+	return []byte(SGR(rewritten).Escape())
+}
+
+// sgrParams returns the parameters of the complete control sequence seq if seq is an SGR. The returned
+// bool is false for all other sequences, including private sequences using m as their final byte.
+func sgrParams[T string | []byte](seq T) (string, bool) {
+	if len(seq) < len(csiBytes)+1 || seq[len(seq)-1] != sgrTerminator {
+		return "", false
+	}
+
+	params := string(seq[len(csiBytes) : len(seq)-1])
+	if strings.IndexFunc(params, func(r rune) bool { return (r < '0' || r > '9') && r != ';' && r != ':' }) >= 0 {
+		return "", false
+	}
+
+	return params, true
+}
+
+// downsampleParams rewrites the SGR parameters params to only use colors supported by p. It returns the
+// rewritten parameters and whether any parameter has been changed.
+func downsampleParams(params string, p ColorProfile) (string, bool) {
+	if params == "" {
+		return params, false
+	}
+
+	parts := strings.Split(params, ";")
+	out := make([]string, 0, len(parts))
+	changed := false
+
+	for i := 0; i < len(parts); i++ {
+		c, target, consumed, ok := parseColorParam(parts[i:])
+		if !ok {
+			out = append(out, parts[i])
+			continue
+		}
+
+		orig := strings.Join(parts[i:i+consumed], ";")
+		i += consumed - 1
+
+		converted := p.Convert(c)
+		if converted == c {
+			// Keep the original notation for colors that need no conversion.
+			out = append(out, orig)
+			continue
+		}
+
+		changed = true
+		if converted == nil {
+			continue
+		}
+
+		switch target {
+		case colorTargetFg:
+			out = append(out, string(converted.Fg()))
+		case colorTargetBg:
+			out = append(out, string(converted.Bg()))
+		default:
+			out = append(out, string(converted.Underline()))
+		}
+	}
+
+	return strings.Join(out, ";"), changed
+}
+
+// colorTarget defines what a color is applied to.
+type colorTarget int
+
+const (
+	colorTargetFg colorTarget = iota
+	colorTargetBg
+	colorTargetUnderline
+)
+
+// parseColorParam parses the color instruction at the beginning of parts. It returns the color, the color's
+// target and the number of parts consumed. ok is false, if parts does not start with a (valid) color
+// instruction.
+func parseColorParam(parts []string) (c Color, target colorTarget, consumed int, ok bool) {
+	if strings.Contains(parts[0], ":") {
+		sub := strings.Split(parts[0], ":")
+		target, ok = extendedColorTarget(sub[0])
+		if !ok || len(sub) < 2 {
+			return nil, 0, 0, false
+		}
+
+		c, ok = parseExtendedColor(sub[1], sub[2:], true)
+		return c, target, 1, ok
+	}
+
+	n, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return nil, 0, 0, false
+	}
+
+	switch {
+	case n >= 30 && n <= 37:
+		return ANSIColor(n - 30), colorTargetFg, 1, true
+	case n >= 90 && n <= 97:
+		return ANSIColor(n - 90 + 8), colorTargetFg, 1, true
+	case n >= 40 && n <= 47:
+		return ANSIColor(n - 40), colorTargetBg, 1, true
+	case n >= 100 && n <= 107:
+		return ANSIColor(n - 100 + 8), colorTargetBg, 1, true
+	}
+
+	target, ok = extendedColorTarget(parts[0])
+	if !ok || len(parts) < 2 {
+		return nil, 0, 0, false
+	}
+
+	switch parts[1] {
+	case "5":
+		if len(parts) < 3 {
+			return nil, 0, 0, false
+		}
+		c, ok = parseExtendedColor("5", parts[2:3], false)
+		return c, target, 3, ok
+	case "2":
+		if len(parts) < 5 {
+			return nil, 0, 0, false
+		}
+		c, ok = parseExtendedColor("2", parts[2:5], false)
+		return c, target, 5, ok
+	}
+
+	return nil, 0, 0, false
+}
+
+// extendedColorTarget returns the target of the extended color instruction p (38, 48 or 58).
+func extendedColorTarget(p string) (colorTarget, bool) {
+	switch p {
+	case "38":
+		return colorTargetFg, true
+	case "48":
+		return colorTargetBg, true
+	case "58":
+		return colorTargetUnderline, true
+	}
+	return 0, false
+}
+
+// parseExtendedColor parses the arguments args of an extended color of the given kind ("5" for 256
+// colors or "2" for RGB colors). If colon is true, args are colon separated sub parameters, which may
+// contain a color space identifier preceding the RGB components.
+func parseExtendedColor(kind string, args []string, colon bool) (Color, bool) {
+	switch kind {
+	case "5":
+		if len(args) != 1 {
+			return nil, false
+		}
+		v, err := strconv.ParseUint(args[0], 10, 8)
+		if err != nil {
+			return nil, false
+		}
+		return ANSI256Color(v), true
+
+	case "2":
+		if colon && len(args) == 4 {
+			// Skip the color space identifier.
+			args = args[1:]
+		}
+		if len(args) != 3 {
+			return nil, false
+		}
+
+		var rgb [3]uint8
+		for i, a := range args {
+			v, err := strconv.ParseUint(a, 10, 8)
+			if err != nil {
+				return nil, false
+			}
+			rgb[i] = uint8(v)
+		}
+		return RGBColor{rgb[0], rgb[1], rgb[2]}, true
+	}
+
+	return nil, false
+}
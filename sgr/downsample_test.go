@@ -0,0 +1,38 @@
+package sgr
+
+import (
+	"testing"
+
+	"github.com/halimath/expect"
+	"github.com/halimath/expect/is"
+)
+
+func TestDownsample(t *testing.T) {
+	type testCase struct {
+		profile ColorProfile
+		in      string
+		want    string
+	}
+
+	tests := []testCase{
+		{ProfileANSI256, "hello", "hello"},
+		{ProfileTrueColor, "\x1b[38;2;255;0;0mred", "\x1b[38;2;255;0;0mred"},
+		{ProfileANSI256, "\x1b[38;2;255;0;0mred\x1b[0m", "\x1b[38;5;196mred\x1b[0m"},
+		{ProfileANSI256, "\x1b[1;48;2;255;0;0;4m", "\x1b[1;48;5;196;4m"},
+		{ProfileANSI256, "\x1b[38:2::255:0:0m", "\x1b[38;5;196m"},
+		{ProfileANSI256, "\x1b[58:2:255:0:0m", "\x1b[58;5;196m"},
+		{ProfileANSI256, "\x1b[38;5;33m", "\x1b[38;5;33m"},
+		{ProfileANSI, "\x1b[38;5;196;42m", "\x1b[91;42m"},
+		{ProfileANSI, "\x1b[38;2;200;10;10mred", "\x1b[31mred"},
+		{ProfileNone, "\x1b[1;31mbold\x1b[0m", "\x1b[1mbold\x1b[0m"},
+		{ProfileNone, "\x1b[31mred\x1b[39m", "red\x1b[39m"},
+		{ProfileNone, "\x1b[2J\x1b[31m", "\x1b[2J"},
+		{ProfileANSI256, "\x1b[38;2;255m", "\x1b[38;2;255m"},
+		{ProfileANSI256, "\x1b[38;2;255;0", "\x1b[38;2;255;0"},
+	}
+
+	for _, test := range tests {
+		expect.WithMessage(t, "%d %q", test.profile, test.in).
+			That(is.EqualTo(string(Downsample([]byte(test.in), test.profile)), test.want))
+	}
+}
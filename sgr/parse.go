@@ -0,0 +1,97 @@
+package sgr
+
+import (
+	"strings"
+
+	"github.com/halimath/terminal/csi"
+)
+
+// Span is a piece of text displayed using a single Style.
+type Span struct {
+	Text  string
+	Style Style
+}
+
+// Parse parses the string s containing text and SGRs (i.e. the output of some command run with colors
+// enabled) into a sequence of spans. The style of each span is the result of applying all SGRs preceding
+// the span's text to the terminal's default rendition (see Style.Update), including 256 and RGB colors
+// given using either semicolon or colon separated parameters. Adjacent text with the same style is combined
+// into a single span; spans never contain empty text.
+//
+// All other control functions (such as cursor movements, window titles or hyperlinks) are removed from the
+// text using csi.Strip. Instructions that cannot be represented by a Style are ignored.
+func Parse(s string) []Span {
+	p := parser{}
+
+	for len(s) > 0 {
+		idx := strings.Index(s, csi.CSI)
+		if idx < 0 {
+			p.text.WriteString(s)
+			break
+		}
+
+		p.text.WriteString(s[:idx])
+		s = s[idx:]
+
+		n, _ := csiLength(s)
+		seq := s[:n]
+		s = s[n:]
+
+		params, ok := sgrParams(seq)
+		if !ok {
+			// Keep the sequence for Strip to remove it (and any control string it may be part of).
+			p.text.WriteString(seq)
+			continue
+		}
+
+		if style, _ := p.style.Update(SGR(params)); style != p.style {
+			p.flush()
+			p.style = style
+		}
+	}
+
+	p.flush()
+
+	return p.spans
+}
+
+// parser implements the state of parsing a string into spans.
+type parser struct {
+	spans []Span
+	style Style
+	text  strings.Builder
+}
+
+// flush adds the collected text using the current style to the spans.
+func (p *parser) flush() {
+	text := string(csi.Strip([]byte(p.text.String())))
+	p.text.Reset()
+
+	if text == "" {
+		return
+	}
+
+	if l := len(p.spans); l > 0 && p.spans[l-1].Style == p.style {
+		p.spans[l-1].Text += text
+		return
+	}
+
+	p.spans = append(p.spans, Span{Text: text, Style: p.style})
+}
+
+// Render renders spans into a string using the minimal SGRs needed to transition from one span's style to
+// the next (see Style.Transition). The returned string starts and ends in the terminal's default rendition.
+func Render(spans []Span) string {
+	var b strings.Builder
+	var current Style
+
+	for _, s := range spans {
+		b.WriteString(s.Style.Transition(current))
+		b.WriteString(s.Text)
+		current = s.Style
+	}
+
+	b.WriteString(Style{}.Transition(current))
+
+	return b.String()
+}
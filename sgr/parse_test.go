@@ -0,0 +1,92 @@
+package sgr
+
+import (
+	"testing"
+
+	"github.com/halimath/expect"
+	"github.com/halimath/expect/is"
+	"github.com/halimath/terminal/csi"
+)
+
+func TestParse(t *testing.T) {
+	type testCase struct {
+		in   string
+		want []Span
+	}
+
+	tests := []testCase{
+		{"", nil},
+		{"plain", []Span{{Text: "plain"}}},
+		{
+			"\x1b[1;31merror:\x1b[0m file not found",
+			[]Span{
+				{Text: "error:", Style: Style{Fg: Red, Attrs: AttrBold}},
+				{Text: " file not found"},
+			},
+		},
+		{
+			"\x1b[38;5;33mblue\x1b[48;2;1;2;3m on rgb\x1b[39;49m",
+			[]Span{
+				{Text: "blue", Style: Style{Fg: ANSI256Color(33)}},
+				{Text: " on rgb", Style: Style{Fg: ANSI256Color(33), Bg: RGBColor{1, 2, 3}}},
+			},
+		},
+		{
+			"\x1b[4:3;58:2::255:0:0mwarn\x1b[4:0;59m",
+			[]Span{{Text: "warn", Style: Style{Underline: UnderlineCurly, UnderlineColor: RGBColor{255, 0, 0}}}},
+		},
+		{
+			"\x1b[32mgr\x1b[0m\x1b[32meen\x1b[m",
+			[]Span{{Text: "green", Style: Style{Fg: Green}}},
+		},
+		{
+			"\x1b[2J" + csi.SetWindowTitle("title") + "\x1b[1m" + csi.Hyperlink("https://example.com", "link", "") + "\x1b[22m",
+			[]Span{{Text: "link", Style: Style{Attrs: AttrBold}}},
+		},
+		{
+			"\x1b[1mbold\x1b[10m still bold",
+			[]Span{{Text: "bold still bold", Style: Style{Attrs: AttrBold}}},
+		},
+		{
+			"\x1b[31mred\x1b[",
+			[]Span{{Text: "red", Style: Style{Fg: Red}}},
+		},
+	}
+
+	for _, test := range tests {
+		expect.WithMessage(t, "%q", test.in).That(is.DeepEqualTo(Parse(test.in), test.want))
+	}
+}
+
+func TestRender(t *testing.T) {
+	type testCase struct {
+		in   []Span
+		want string
+	}
+
+	tests := []testCase{
+		{nil, ""},
+		{[]Span{{Text: "plain"}}, "plain"},
+		{
+			[]Span{
+				{Text: "error:", Style: Style{Fg: Red, Attrs: AttrBold}},
+				{Text: " details", Style: Style{Fg: Red}},
+				{Text: " plain"},
+			},
+			"\x1b[1;31merror:\x1b[22m details\x1b[39m plain",
+		},
+		{
+			[]Span{{Text: "warn", Style: Style{Underline: UnderlineCurly, UnderlineColor: ANSI256Color(208)}}},
+			"\x1b[4:3;58;5;208mwarn\x1b[0m",
+		},
+	}
+
+	for _, test := range tests {
+		expect.WithMessage(t, "%#v", test.in).That(is.EqualTo(Render(test.in), test.want))
+	}
+}
+
+func TestParse_roundTrip(t *testing.T) {
+	in := "\x1b[1m\x1b[31merror\x1b[0m\x1b[0m: \x1b[33mwarning\x1b[0m"
+	expect.That(t, is.EqualTo(Render(Parse(in)), "\x1b[1;31merror\x1b[0m: \x1b[33mwarning\x1b[39m"))
+}
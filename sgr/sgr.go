@@ -11,7 +11,6 @@
 package sgr
 
 import (
-	"bytes"
 	"fmt"
 	"strings"
 
@@ -44,9 +43,28 @@ func (s SGR) Join(others ...SGR) SGR {
 	return SGR(b.String())
 }
 
-// Apply applies s to str and returns the returning string.
+// Apply applies s to str and returns the returning string. The returned string ends with targeted resets
+// for all instructions contained in s (i.e. NotItalic for Italic or DefaultFg for a foreground color), so
+// applying s to a string nested in some other styled string does not reset the enclosing style. If s
+// contains instructions without a targeted reset, ResetAll is used.
 func (s SGR) Apply(str any) string {
-	return s.Escape() + fmt.Sprint(str) + ResetAll.Escape()
+	reset := s.Reset()
+	if reset == "" {
+		return s.Escape() + fmt.Sprint(str)
+	}
+	return s.Escape() + fmt.Sprint(str) + reset.Escape()
+}
+
+// Reset returns the SGR that turns off all instructions contained in s using targeted resets. If s contains
+// instructions without a targeted reset, ResetAll is returned. If s contains no instructions to turn off,
+// the empty SGR is returned. Note that the empty SGR, when escaped, is equivalent to ResetAll.
+func (s SGR) Reset() SGR {
+	style, ok := Style{}.Update(s)
+	if !ok {
+		return ResetAll
+	}
+
+	return join(Style{}.diff(style))
 }
 
 // Applyf applies s to the string produced by formatting format with args  and returns the returning string.
@@ -56,48 +74,52 @@ func (s SGR) Applyf(format string, args ...any) string {
 
 var csiBytes = []byte(csi.CSI)
 
-// Remove removes all SGRs on b and returns the bare bytes.
+// Remove removes all SGRs on b and returns the bare bytes. In addition, Remove drops all other control
+// functions, such as hyperlinks (the link's text is kept), window titles or cursor movements, as they are
+// meaningless when b is not displayed by a terminal.
+//
+// Deprecated: Remove is kept for compatibility and delegates to csi.Strip. Use csi.Strip instead.
 func Remove(b []byte) []byte {
-	// First, see if there are SGRs as part of the string. If not, its safe
-	// to return s directly which improves memory cost and thus the overall
-	// performance of this function.
-	if !bytes.Contains(b, csiBytes) {
-		return b
-	}
-
-	var buf bytes.Buffer
-	buf.Grow(len(b))
-
-	var inSgr bool
-	for i, v := range b {
-		if inSgr {
-			if v == sgrTerminator {
-				inSgr = false
-			}
-
-			continue
-		}
-
-		if bytes.HasPrefix(b[i:], csiBytes) {
-			inSgr = true
-			continue
-		}
-
-		buf.WriteByte(v)
-	}
-
-	return buf.Bytes()
+	return csi.Strip(b)
 }
 
 const (
 	// Basic rendition instructions
-	ResetAll   SGR = "0" // reset all SGR effects to their default
-	Bold       SGR = "1" // bold or increased intensity
-	Faint      SGR = "2" // faint or decreased intensity
-	Italic     SGR = "3" // Italic mode
-	Underlined SGR = "4" // singly underlined
-	Blink      SGR = "5" // slow blink
-	Invert     SGR = "7" // Invert Fg/Bg colors
+	ResetAll    SGR = "0"  // reset all SGR effects to their default
+	Bold        SGR = "1"  // bold or increased intensity
+	Faint       SGR = "2"  // faint or decreased intensity
+	Italic      SGR = "3"  // Italic mode
+	Underlined  SGR = "4"  // singly underlined
+	Blink       SGR = "5"  // slow blink
+	RapidBlink  SGR = "6"  // rapid blink
+	Invert      SGR = "7"  // Invert Fg/Bg colors
+	Conceal     SGR = "8"  // conceal (hide) text
+	Strike      SGR = "9"  // crossed-out text
+	Overline    SGR = "53" // overlined text
+	Superscript SGR = "73" // superscript (mintty)
+	Subscript   SGR = "74" // subscript (mintty)
+
+	// Targeted resets turning off individual rendition instructions
+	NormalIntensity     SGR = "22" // neither bold nor faint
+	NotItalic           SGR = "23" // turn off italic
+	NotUnderlined       SGR = "24" // turn off all underline styles
+	NotBlinking         SGR = "25" // turn off slow and rapid blink
+	NotInverted         SGR = "27" // turn off inverted colors
+	Reveal              SGR = "28" // turn off conceal
+	NotStrike           SGR = "29" // turn off crossed-out
+	NotOverlined        SGR = "55" // turn off overline
+	NotSuperOrSubscript SGR = "75" // turn off superscript and subscript (mintty)
+	DefaultFg           SGR = "39" // reset the foreground color to the default
+	DefaultBg           SGR = "49" // reset the background color to the default
+
+	// Extended underline styles. Terminals not supporting these styles may ignore them or fall back to a
+	// single underline. See RemoveExtendedUnderline for converting them to a single underline.
+	DoublyUnderlined    SGR = "21"  // doubly underlined (ECMA-48)
+	UnderlinedDouble    SGR = "4:2" // doubly underlined (kitty style sub parameter)
+	UnderlinedCurly     SGR = "4:3" // curly underline
+	UnderlinedDotted    SGR = "4:4" // dotted underline
+	UnderlinedDashed    SGR = "4:5" // dashed underline
+	ResetUnderlineColor SGR = "59"  // reset the underline color to the default (the foreground color)
 
 	// Rendition instructions for standard foregroud colors
 	FgBlack   SGR = "30"
@@ -169,6 +191,16 @@ func FgTrueColor(r, g, b uint8) SGR {
 	return SGR(fmt.Sprintf("38;2;%d;%d;%d", r, g, b))
 }
 
+// UnderlineColor256 creates a SGR that sets the underline color to the 256 color palette entry n.
+func UnderlineColor256(n uint8) SGR {
+	return SGR(fmt.Sprintf("58;5;%d", n))
+}
+
+// UnderlineTrueColor creates a SGR that sets the underline color to the true color value given with r, g, b.
+func UnderlineTrueColor(r, g, b uint8) SGR {
+	return SGR(fmt.Sprintf("58;2;%d;%d;%d", r, g, b))
+}
+
 // BgTrueColor creates a SGR that sets the background color to the true color value given with r, g, b.
 func BgTrueColor(r, g, b uint8) SGR {
 	return SGR(fmt.Sprintf("48;2;%d;%d;%d", r, g, b))
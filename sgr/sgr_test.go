@@ -6,6 +6,7 @@ import (
 
 	"github.com/halimath/expect"
 	"github.com/halimath/expect/is"
+	"github.com/halimath/terminal/csi"
 )
 
 func TestEscape(t *testing.T) {
@@ -57,17 +58,48 @@ func TestBgTrueColor(t *testing.T) {
 }
 
 func TestApply(t *testing.T) {
-	expect.That(t, is.EqualTo(FgRed.Apply("hello, world"), "\x1B[31mhello, world\x1B[0m"))
+	expect.That(t,
+		is.EqualTo(FgRed.Apply("hello, world"), "\x1B[31mhello, world\x1B[39m"),
+		is.EqualTo(Bold.Join(Italic, BgTrueColor(1, 2, 3)).Apply("foo"), "\x1B[1;3;48;2;1;2;3mfoo\x1B[22;23;49m"),
+		is.EqualTo(FgRed.Apply("a"+Bold.Apply("b")+"c"), "\x1B[31ma\x1B[1mb\x1B[22mc\x1B[39m"),
+		is.EqualTo(SGR("51").Apply("framed"), "\x1B[51mframed\x1B[0m"),
+		is.EqualTo(ResetAll.Apply("plain"), "\x1B[0mplain"),
+	)
 }
 
 func TestApplyf(t *testing.T) {
-	expect.That(t, is.EqualTo(FgRed.Applyf("hello, %s", "world"), "\x1B[31mhello, world\x1B[0m"))
+	expect.That(t, is.EqualTo(FgRed.Applyf("hello, %s", "world"), "\x1B[31mhello, world\x1B[39m"))
+}
+
+func TestReset(t *testing.T) {
+	tests := map[SGR]SGR{
+		Bold:                        NormalIntensity,
+		Bold.Join(Faint):            NormalIntensity,
+		RapidBlink:                  NotBlinking,
+		Conceal.Join(Strike):        Reveal.Join(NotStrike),
+		Overline:                    NotOverlined,
+		Superscript:                 NotSuperOrSubscript,
+		UnderlinedCurly:             NotUnderlined,
+		UnderlineColor256(3):        ResetUnderlineColor,
+		FgRGB(1, 2, 3).Join(BgBlue): DefaultFg.Join(DefaultBg),
+		ResetAll:                    "",
+		ResetAll.Join(Italic):       NotItalic,
+		SGR("12"):                   ResetAll,
+	}
+
+	for in, want := range tests {
+		expect.WithMessage(t, "%q", in).That(is.EqualTo(in.Reset(), want))
+	}
 }
 
 func TestRemove(t *testing.T) {
 	tests := map[string]string{
 		"foobar":          "foobar",
 		Bold.Apply("foo"): "foo",
+		csi.Hyperlink("https://example.com", "foo", "1") + Bold.Apply("bar"): "foobar",
+		csi.SetWindowTitle("title") + "foo":                                  "foo",
+		"\x1b]2;title\afoo":                                                  "foo",
+		"\x1b[2Jfoo bar m":                                                   "foo bar m",
 	}
 
 	for in, want := range tests {
@@ -0,0 +1,333 @@
+package sgr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Attr is a bit set of text attributes.
+type Attr uint16
+
+const (
+	AttrBold Attr = 1 << iota
+	AttrFaint
+	AttrItalic
+	AttrBlink
+	AttrInvert
+	AttrRapidBlink
+	AttrConceal
+	AttrStrike
+	AttrOverline
+	AttrSuperscript
+	AttrSubscript
+)
+
+// attrDef defines the SGR to set and reset an attribute.
+type attrDef struct {
+	attr     Attr
+	set      SGR
+	reset    SGR
+	resetAll Attr // all attributes turned off by reset
+}
+
+// attrDefs lists all attributes in the order they are rendered.
+var attrDefs = []attrDef{
+	{AttrBold, Bold, NormalIntensity, AttrBold | AttrFaint},
+	{AttrFaint, Faint, NormalIntensity, AttrBold | AttrFaint},
+	{AttrItalic, Italic, NotItalic, AttrItalic},
+	{AttrBlink, Blink, NotBlinking, AttrBlink | AttrRapidBlink},
+	{AttrRapidBlink, RapidBlink, NotBlinking, AttrBlink | AttrRapidBlink},
+	{AttrInvert, Invert, NotInverted, AttrInvert},
+	{AttrConceal, Conceal, Reveal, AttrConceal},
+	{AttrStrike, Strike, NotStrike, AttrStrike},
+	{AttrOverline, Overline, NotOverlined, AttrOverline},
+	{AttrSuperscript, Superscript, NotSuperOrSubscript, AttrSuperscript | AttrSubscript},
+	{AttrSubscript, Subscript, NotSuperOrSubscript, AttrSuperscript | AttrSubscript},
+}
+
+// UnderlineStyle defines the style used to underline text.
+type UnderlineStyle uint8
+
+const (
+	UnderlineNone UnderlineStyle = iota
+	UnderlineSingle
+	UnderlineDouble
+	UnderlineCurly
+	UnderlineDotted
+	UnderlineDashed
+)
+
+// sgr returns the SGR to activate u.
+func (u UnderlineStyle) sgr() SGR {
+	switch u {
+	case UnderlineNone:
+		return NotUnderlined
+	case UnderlineSingle:
+		return Underlined
+	case UnderlineDouble:
+		return UnderlinedDouble
+	case UnderlineCurly:
+		return UnderlinedCurly
+	case UnderlineDotted:
+		return UnderlinedDotted
+	case UnderlineDashed:
+		return UnderlinedDashed
+	default:
+		return SGR(fmt.Sprintf("4:%d", u))
+	}
+}
+
+// Style is a structured representation of text rendition. In contrast to an SGR, a Style can be inspected,
+// merged and compared. The zero value represents the terminal's default rendition. Colors set to nil
+// represent the terminal's default color.
+//
+// Styles are values and all methods return new values leaving the receiver unchanged.
+type Style struct {
+	Fg, Bg         Color
+	UnderlineColor Color
+	Attrs          Attr
+	Underline      UnderlineStyle
+}
+
+// Has returns whether all attributes in a are set in s.
+func (s Style) Has(a Attr) bool {
+	return s.Attrs&a == a
+}
+
+// Equal returns whether s and o define the same rendition.
+func (s Style) Equal(o Style) bool {
+	return s == o
+}
+
+// Merge returns a new Style with all values set in o overriding the values of s. Attributes are combined.
+func (s Style) Merge(o Style) Style {
+	if o.Fg != nil {
+		s.Fg = o.Fg
+	}
+	if o.Bg != nil {
+		s.Bg = o.Bg
+	}
+	if o.UnderlineColor != nil {
+		s.UnderlineColor = o.UnderlineColor
+	}
+	if o.Underline != UnderlineNone {
+		s.Underline = o.Underline
+	}
+	s.Attrs |= o.Attrs
+
+	return s
+}
+
+// Inherit returns a new Style with all values not set in s taken from parent. Attributes are combined.
+// s.Inherit(parent) is equivalent to parent.Merge(s).
+func (s Style) Inherit(parent Style) Style {
+	return parent.Merge(s)
+}
+
+// SGR returns the SGR that activates s starting from the terminal's default rendition. For the zero
+// Style, ResetAll is returned.
+func (s Style) SGR() SGR {
+	params := s.params()
+	if len(params) == 0 {
+		return ResetAll
+	}
+	return join(params)
+}
+
+// Apply applies s to str and returns the resulting string. The returned string ends with targeted resets
+// for all values set by s, so applying s to a string nested in some other styled string does not reset the
+// enclosing style.
+func (s Style) Apply(str any) string {
+	if s == (Style{}) {
+		return fmt.Sprint(str)
+	}
+
+	return s.Transition(Style{}) + fmt.Sprint(str) + join(Style{}.diff(s)).Escape()
+}
+
+// Transition returns the escape sequence with the minimal number of parameters that changes the rendition
+// from from to s. Targeted resets are preferred over a full reset unless the full reset requires fewer
+// parameters. If both styles are equal, an empty string is returned.
+func (s Style) Transition(from Style) string {
+	if s == from {
+		return ""
+	}
+
+	diff := s.diff(from)
+	full := append([]SGR{ResetAll}, s.params()...)
+
+	if len(full) < len(diff) {
+		return join(full).Escape()
+	}
+
+	return join(diff).Escape()
+}
+
+// params returns the SGRs needed to activate s starting from the default rendition.
+func (s Style) params() []SGR {
+	var params []SGR
+
+	for _, d := range attrDefs {
+		if s.Attrs&d.attr != 0 {
+			params = append(params, d.set)
+		}
+	}
+
+	if s.Underline != UnderlineNone {
+		params = append(params, s.Underline.sgr())
+	}
+	if s.Fg != nil {
+		params = append(params, s.Fg.Fg())
+	}
+	if s.Bg != nil {
+		params = append(params, s.Bg.Bg())
+	}
+	if s.UnderlineColor != nil {
+		params = append(params, s.UnderlineColor.Underline())
+	}
+
+	return params
+}
+
+// diff returns the SGRs needed to change from from to s using targeted resets.
+func (s Style) diff(from Style) []SGR {
+	var params []SGR
+
+	// First, reset all removed attributes. As some resets turn off multiple attributes, remember which
+	// attributes need to be set again afterwards.
+	removed := from.Attrs &^ s.Attrs
+	set := s.Attrs &^ from.Attrs
+	var resetDone Attr
+	for _, d := range attrDefs {
+		if removed&d.attr != 0 && resetDone&d.attr == 0 {
+			params = append(params, d.reset)
+			resetDone |= d.resetAll
+			set |= s.Attrs & d.resetAll
+		}
+	}
+
+	for _, d := range attrDefs {
+		if set&d.attr != 0 {
+			params = append(params, d.set)
+		}
+	}
+
+	if s.Underline != from.Underline {
+		params = append(params, s.Underline.sgr())
+	}
+
+	if s.Fg != from.Fg {
+		if s.Fg == nil {
+			params = append(params, DefaultFg)
+		} else {
+			params = append(params, s.Fg.Fg())
+		}
+	}
+
+	if s.Bg != from.Bg {
+		if s.Bg == nil {
+			params = append(params, DefaultBg)
+		} else {
+			params = append(params, s.Bg.Bg())
+		}
+	}
+
+	if s.UnderlineColor != from.UnderlineColor {
+		if s.UnderlineColor == nil {
+			params = append(params, ResetUnderlineColor)
+		} else {
+			params = append(params, s.UnderlineColor.Underline())
+		}
+	}
+
+	return params
+}
+
+// Update returns a new Style with all instructions contained in the SGR params applied to s. This allows
+// tracking the rendition while processing a stream of SGRs. Instructions that cannot be represented by a
+// Style (such as font selection) are ignored, in which case the returned bool is false.
+func (s Style) Update(params SGR) (Style, bool) {
+	if params == "" {
+		return Style{}, true
+	}
+
+	ok := true
+	parts := strings.Split(string(params), string(sgrSeparator))
+	for i := 0; i < len(parts); i++ {
+		if c, target, consumed, ok := parseColorParam(parts[i:]); ok {
+			switch target {
+			case colorTargetFg:
+				s.Fg = c
+			case colorTargetBg:
+				s.Bg = c
+			default:
+				s.UnderlineColor = c
+			}
+			i += consumed - 1
+			continue
+		}
+
+		p := SGR(parts[i])
+		if strings.HasPrefix(parts[i], "4:") {
+			u, err := strconv.ParseUint(parts[i][2:], 10, 8)
+			if err != nil || u > uint64(UnderlineDashed) {
+				ok = false
+				continue
+			}
+			s.Underline = UnderlineStyle(u)
+			continue
+		}
+
+		switch p {
+		case "", ResetAll, "00":
+			s = Style{}
+		case Underlined:
+			s.Underline = UnderlineSingle
+		case DoublyUnderlined:
+			s.Underline = UnderlineDouble
+		case NotUnderlined:
+			s.Underline = UnderlineNone
+		case DefaultFg:
+			s.Fg = nil
+		case DefaultBg:
+			s.Bg = nil
+		case ResetUnderlineColor:
+			s.UnderlineColor = nil
+		default:
+			if !s.applyAttr(p) {
+				ok = false
+			}
+		}
+	}
+
+	return s, ok
+}
+
+// applyAttr sets or resets the attribute p on s. It returns false, if p does not define an attribute.
+func (s *Style) applyAttr(p SGR) bool {
+	for _, d := range attrDefs {
+		switch p {
+		case d.set:
+			// Superscript and subscript are mutually exclusive.
+			s.Attrs = s.Attrs&^(d.resetAll&(AttrSuperscript|AttrSubscript)) | d.attr
+			return true
+		case d.reset:
+			s.Attrs &^= d.resetAll
+			return true
+		}
+	}
+	return false
+}
+
+// join joins params into a single SGR.
+func join(params []SGR) SGR {
+	var b strings.Builder
+	for i, p := range params {
+		if i > 0 {
+			b.WriteByte(sgrSeparator)
+		}
+		b.WriteString(string(p))
+	}
+	return SGR(b.String())
+}
@@ -0,0 +1,107 @@
+package sgr
+
+import (
+	"testing"
+
+	"github.com/halimath/expect"
+	"github.com/halimath/expect/is"
+)
+
+func TestStyle_Has(t *testing.T) {
+	s := Style{Attrs: AttrBold | AttrItalic}
+	expect.That(t,
+		is.EqualTo(s.Has(AttrBold), true),
+		is.EqualTo(s.Has(AttrBold|AttrItalic), true),
+		is.EqualTo(s.Has(AttrBold|AttrFaint), false),
+	)
+}
+
+func TestStyle_Equal(t *testing.T) {
+	expect.That(t,
+		is.EqualTo(Style{Fg: Red}.Equal(Style{Fg: Red}), true),
+		is.EqualTo(Style{Fg: RGBColor{1, 2, 3}}.Equal(Style{Fg: RGBColor{1, 2, 3}}), true),
+		is.EqualTo(Style{Fg: Red}.Equal(Style{Fg: ANSI256Color(1)}), false),
+		is.EqualTo(Style{Fg: Red}.Equal(Style{Fg: Red, Attrs: AttrBold}), false),
+	)
+}
+
+func TestStyle_Merge(t *testing.T) {
+	base := Style{Fg: Red, Bg: Blue, Attrs: AttrBold}
+	got := base.Merge(Style{Fg: Green, Attrs: AttrItalic, Underline: UnderlineCurly})
+
+	expect.That(t,
+		is.EqualTo(got, Style{Fg: Green, Bg: Blue, Attrs: AttrBold | AttrItalic, Underline: UnderlineCurly}),
+		is.EqualTo(base, Style{Fg: Red, Bg: Blue, Attrs: AttrBold}),
+	)
+}
+
+func TestStyle_Inherit(t *testing.T) {
+	got := Style{Fg: Green}.Inherit(Style{Fg: Red, Bg: Blue, Attrs: AttrBold})
+	expect.That(t, is.EqualTo(got, Style{Fg: Green, Bg: Blue, Attrs: AttrBold}))
+}
+
+func TestStyle_SGR(t *testing.T) {
+	expect.That(t,
+		is.EqualTo(Style{}.SGR(), ResetAll),
+		is.EqualTo(Style{Fg: Red, Bg: LightBlue, Attrs: AttrBold | AttrInvert}.SGR(), "1;7;31;104"),
+		is.EqualTo(Style{Underline: UnderlineCurly, UnderlineColor: RGBColor{255, 0, 0}}.SGR(), "4:3;58;2;255;0;0"),
+		is.EqualTo(Style{Fg: ANSI256Color(33), Bg: RGBColor{1, 2, 3}}.SGR(), "38;5;33;48;2;1;2;3"),
+	)
+}
+
+func TestStyle_Transition(t *testing.T) {
+	type testCase struct {
+		from, to Style
+		want     string
+	}
+
+	tests := []testCase{
+		{Style{}, Style{}, ""},
+		{Style{Fg: Red}, Style{Fg: Red}, ""},
+		{Style{}, Style{Fg: Red}, "\x1b[31m"},
+		{Style{Fg: Red, Attrs: AttrBold}, Style{Fg: Green, Attrs: AttrBold}, "\x1b[32m"},
+		{Style{Fg: Red}, Style{}, "\x1b[39m"},
+		{Style{Attrs: AttrBold | AttrFaint}, Style{Attrs: AttrFaint}, "\x1b[22;2m"},
+		{Style{Fg: Red, Bg: Blue, Attrs: AttrBold | AttrItalic, Underline: UnderlineSingle}, Style{}, "\x1b[0m"},
+		{Style{Fg: Red, Bg: Blue, Attrs: AttrItalic}, Style{Attrs: AttrBold}, "\x1b[0;1m"},
+		{Style{Underline: UnderlineSingle}, Style{Underline: UnderlineDouble}, "\x1b[4:2m"},
+	}
+
+	for _, test := range tests {
+		expect.WithMessage(t, "%v -> %v", test.from, test.to).
+			That(is.EqualTo(test.to.Transition(test.from), test.want))
+	}
+}
+
+func TestStyle_Apply(t *testing.T) {
+	expect.That(t,
+		is.EqualTo(Style{}.Apply("foo"), "foo"),
+		is.EqualTo(Style{Fg: Red, Attrs: AttrBold}.Apply("foo"), "\x1b[1;31mfoo\x1b[22;39m"),
+	)
+}
+
+func TestStyle_Update(t *testing.T) {
+	type testCase struct {
+		from   Style
+		params SGR
+		want   Style
+		ok     bool
+	}
+
+	tests := []testCase{
+		{Style{}, Bold.Join(FgRed), Style{Fg: Red, Attrs: AttrBold}, true},
+		{Style{Fg: Red, Attrs: AttrBold}, NormalIntensity, Style{Fg: Red}, true},
+		{Style{Fg: Red, Attrs: AttrBold}, "", Style{}, true},
+		{Style{Fg: Red}, "0;48;5;33", Style{Bg: ANSI256Color(33)}, true},
+		{Style{}, "4:3;58:2::255:0:0", Style{Underline: UnderlineCurly, UnderlineColor: RGBColor{255, 0, 0}}, true},
+		{Style{Attrs: AttrSuperscript}, Subscript, Style{Attrs: AttrSubscript}, true},
+		{Style{Underline: UnderlineCurly}, NotUnderlined.Join(DefaultFg), Style{}, true},
+		{Style{}, "12;1", Style{Attrs: AttrBold}, false},
+	}
+
+	for _, test := range tests {
+		got, ok := test.from.Update(test.params)
+		expect.WithMessage(t, "%#v + %q", test.from, test.params).
+			That(is.EqualTo(got, test.want), is.EqualTo(ok, test.ok))
+	}
+}
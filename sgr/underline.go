@@ -0,0 +1,52 @@
+package sgr
+
+import "strings"
+
+// RemoveExtendedUnderline rewrites all SGRs contained in b to only use instructions understood by terminals
+// without support for extended underlines. Extended underline styles (such as curly or dotted) are replaced
+// with a single underline and underline colors are removed. All other bytes are left unchanged.
+func RemoveExtendedUnderline(b []byte) []byte {
+	return rewriteSGRs(b, removeExtendedUnderlineParams)
+}
+
+// removeExtendedUnderlineParams rewrites the SGR parameters params to not use extended underlines. It
+// returns the rewritten parameters and whether any parameter has been changed.
+func removeExtendedUnderlineParams(params string) (string, bool) {
+	if params == "" {
+		return params, false
+	}
+
+	parts := strings.Split(params, ";")
+	out := make([]string, 0, len(parts))
+	changed := false
+
+	for i := 0; i < len(parts); i++ {
+		if _, target, consumed, ok := parseColorParam(parts[i:]); ok {
+			if target == colorTargetUnderline {
+				changed = true
+			} else {
+				out = append(out, parts[i:i+consumed]...)
+			}
+			i += consumed - 1
+			continue
+		}
+
+		switch {
+		case parts[i] == string(ResetUnderlineColor):
+			changed = true
+		case parts[i] == string(DoublyUnderlined):
+			out = append(out, string(Underlined))
+			changed = true
+		case parts[i] == "4:0":
+			out = append(out, "24")
+			changed = true
+		case strings.HasPrefix(parts[i], "4:"):
+			out = append(out, string(Underlined))
+			changed = true
+		default:
+			out = append(out, parts[i])
+		}
+	}
+
+	return strings.Join(out, ";"), changed
+}
@@ -0,0 +1,38 @@
+package sgr
+
+import (
+	"testing"
+
+	"github.com/halimath/expect"
+	"github.com/halimath/expect/is"
+)
+
+func TestRemoveExtendedUnderline(t *testing.T) {
+	type testCase struct {
+		in, want string
+	}
+
+	tests := []testCase{
+		{"hello", "hello"},
+		{"\x1b[4mhello\x1b[24m", "\x1b[4mhello\x1b[24m"},
+		{"\x1b[4:3mhello\x1b[4:0m", "\x1b[4mhello\x1b[24m"},
+		{"\x1b[1;21;31m", "\x1b[1;4;31m"},
+		{"\x1b[4:3;58;2;255;0;0mwarn\x1b[59;24m", "\x1b[4mwarn\x1b[24m"},
+		{"\x1b[58:5:196;38;5;196m", "\x1b[38;5;196m"},
+		{"\x1b[58;5;196m", ""},
+		{"\x1b[2J\x1b[4:4m", "\x1b[2J\x1b[4m"},
+	}
+
+	for _, test := range tests {
+		expect.WithMessage(t, "%q", test.in).
+			That(is.EqualTo(string(RemoveExtendedUnderline([]byte(test.in))), test.want))
+	}
+}
+
+func TestUnderlineColor(t *testing.T) {
+	expect.That(t,
+		is.EqualTo(UnderlineColor256(196), "58;5;196"),
+		is.EqualTo(UnderlineTrueColor(255, 128, 0), "58;2;255;128;0"),
+		is.EqualTo(UnderlinedCurly.Join(UnderlineTrueColor(255, 0, 0)).Escape(), "\x1b[4:3;58;2;255;0;0m"),
+	)
+}
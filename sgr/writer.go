@@ -0,0 +1,133 @@
+package sgr
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/halimath/terminal/csi"
+)
+
+// maxPendingLength defines the maximum number of bytes a Writer holds back while waiting for the remainder
+// of a control sequence. Longer sequences are written unchanged.
+const maxPendingLength = 256
+
+// Writer is an io.Writer that downsamples all SGRs written to it to the colors supported by a ColorProfile
+// (see Downsample) before writing them to an underlying io.Writer. If extended underlines are disabled,
+// Writer also replaces them with a single underline (see RemoveExtendedUnderline). Control sequences split across multiple
+// calls to Write are handled by holding back the incomplete sequence until the remainder has been written.
+// Call Flush to write any held back bytes.
+type Writer struct {
+	w       io.Writer
+	profile ColorProfile
+	pending []byte
+
+	noExtendedUnderline bool
+}
+
+// NewWriter creates a new Writer writing to w and downsampling colors to p. Extended underlines are
+// enabled.
+func NewWriter(w io.Writer, p ColorProfile) *Writer {
+	return &Writer{
+		w:       w,
+		profile: p,
+	}
+}
+
+// Profile returns the color profile used by w.
+func (w *Writer) Profile() ColorProfile {
+	return w.profile
+}
+
+// SetProfile sets the color profile used by w for all subsequent writes.
+func (w *Writer) SetProfile(p ColorProfile) {
+	w.profile = p
+}
+
+// ExtendedUnderline returns whether w passes extended underline styles and underline colors through.
+func (w *Writer) ExtendedUnderline() bool {
+	return !w.noExtendedUnderline
+}
+
+// SetExtendedUnderline enables or disables extended underline styles and underline colors for all
+// subsequent writes. When disabled, extended underline styles are replaced with a single underline and
+// underline colors are removed.
+func (w *Writer) SetExtendedUnderline(enabled bool) {
+	w.noExtendedUnderline = !enabled
+}
+
+// Write writes p to the underlying io.Writer after downsampling all SGRs contained in p. An incomplete
+// control sequence at the end of p is held back until the next call to Write or Flush. Write returns
+// len(p) unless an error occured.
+func (w *Writer) Write(p []byte) (int, error) {
+	if len(w.pending) == 0 && w.profile >= ProfileTrueColor && !w.noExtendedUnderline {
+		if _, err := w.w.Write(p); err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	}
+
+	// Keep the bytes held back from previous calls until b has been written successfully. On error, Write
+	// reports 0 bytes written and the caller may retry p.
+	pending := w.pending
+
+	b := p
+	if len(pending) > 0 {
+		b = append(pending[:len(pending):len(pending)], p...)
+		w.pending = nil
+	}
+
+	if cut := incompleteSequenceStart(b); cut >= 0 {
+		w.pending = append([]byte(nil), b[cut:]...)
+		b = b[:cut]
+	}
+
+	if len(b) > 0 {
+		b = Downsample(b, w.profile)
+		if w.noExtendedUnderline {
+			b = RemoveExtendedUnderline(b)
+		}
+
+		if _, err := w.w.Write(b); err != nil {
+			w.pending = pending
+			return 0, err
+		}
+	}
+
+	return len(p), nil
+}
+
+// Flush writes all bytes held back by w to the underlying io.Writer unchanged.
+func (w *Writer) Flush() error {
+	if len(w.pending) == 0 {
+		return nil
+	}
+
+	_, err := w.w.Write(w.pending)
+	w.pending = nil
+	return err
+}
+
+// incompleteSequenceStart returns the index of an incomplete control sequence at the end of b or -1 if b
+// does not end with an incomplete control sequence. Sequences longer than maxPendingLength are not
+// considered incomplete.
+func incompleteSequenceStart(b []byte) int {
+	idx := bytes.LastIndexByte(b, csi.ESC[0])
+	if idx < 0 || len(b)-idx > maxPendingLength {
+		return -1
+	}
+
+	tail := b[idx:]
+	if len(tail) == 1 {
+		return idx
+	}
+
+	if !bytes.HasPrefix(tail, csiBytes) {
+		return -1
+	}
+
+	if _, complete := csiLength(tail); complete {
+		return -1
+	}
+
+	return idx
+}
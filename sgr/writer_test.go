@@ -0,0 +1,92 @@
+package sgr
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/halimath/expect"
+	"github.com/halimath/expect/is"
+)
+
+func TestWriter(t *testing.T) {
+	type testCase struct {
+		profile ColorProfile
+		in      []string
+		want    string
+	}
+
+	tests := []testCase{
+		{ProfileTrueColor, []string{"\x1b[38;2;255;0;0mred", "\x1b[0m"}, "\x1b[38;2;255;0;0mred\x1b[0m"},
+		{ProfileANSI256, []string{"\x1b[38;2;255;0;0mred\x1b[0m"}, "\x1b[38;5;196mred\x1b[0m"},
+		{ProfileANSI256, []string{"a\x1b", "[38;2;255;0;0mred"}, "a\x1b[38;5;196mred"},
+		{ProfileANSI256, []string{"a\x1b[38;2;2", "55;0", ";0mred"}, "a\x1b[38;5;196mred"},
+		{ProfileANSI, []string{"\x1b[48;5;196", "mred\x1b[2J"}, "\x1b[101mred\x1b[2J"},
+		{ProfileANSI, []string{"\x1b]0;title\x07", "text"}, "\x1b]0;title\x07text"},
+		{ProfileNone, []string{"\x1b[1;3", "1mbold"}, "\x1b[1mbold"},
+		{ProfileANSI256, []string{"text\x1b[38;2"}, "text\x1b[38;2"},
+	}
+
+	for _, test := range tests {
+		var buf bytes.Buffer
+		w := NewWriter(&buf, test.profile)
+
+		for _, s := range test.in {
+			n, err := w.Write([]byte(s))
+			expect.That(t, is.NoError(err), is.EqualTo(n, len(s)))
+		}
+
+		expect.That(t, is.NoError(w.Flush()))
+
+		expect.WithMessage(t, "%q", test.in).That(is.EqualTo(buf.String(), test.want))
+	}
+}
+
+func TestWriter_extendedUnderline(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf, ProfileTrueColor)
+	w.SetExtendedUnderline(false)
+
+	_, err := w.Write([]byte("\x1b[4:3;58;2;255;0;0mwarn\x1b[4"))
+	expect.That(t, is.NoError(err))
+	_, err = w.Write([]byte(":0;59m"))
+	expect.That(t, is.NoError(err))
+
+	expect.That(t,
+		is.EqualTo(w.ExtendedUnderline(), false),
+		is.EqualTo(buf.String(), "\x1b[4mwarn\x1b[24m"),
+	)
+}
+
+var errWriteFailed = errors.New("write failed")
+
+type failingWriter struct {
+	bytes.Buffer
+	fail bool
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if w.fail {
+		return 0, errWriteFailed
+	}
+	return w.Buffer.Write(p)
+}
+
+func TestWriter_writeError(t *testing.T) {
+	var fw failingWriter
+	w := NewWriter(&fw, ProfileANSI256)
+
+	_, err := w.Write([]byte("a\x1b[38;2;2"))
+	expect.That(t, is.NoError(err))
+
+	fw.fail = true
+	n, err := w.Write([]byte("55;0;0mred"))
+	expect.That(t, is.Error(err, errWriteFailed), is.EqualTo(n, 0))
+
+	fw.fail = false
+	_, err = w.Write([]byte("55;0;0mred"))
+	expect.That(t,
+		is.NoError(err),
+		is.EqualTo(fw.String(), "a\x1b[38;5;196mred"),
+	)
+}
@@ -5,7 +5,11 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"sync"
 
+	"github.com/halimath/terminal/csi"
 	"github.com/halimath/terminal/input"
 	"github.com/halimath/terminal/rawmode"
 	"github.com/halimath/terminal/sgr"
@@ -17,19 +21,88 @@ var (
 	ErrRawMode = errors.New("failed to activate raw mode")
 )
 
-// TruecolorSupported returns whether the environment this process runs in supports truecolor.
-// This function checks the environment variable COLORTERM to be set to "truecolor".
+// IsTruecolorSupported returns whether the environment this process runs in supports truecolor.
+// This function checks the environment variable COLORTERM to be set to "truecolor" or "24bit". Use
+// DetectColorProfile for a more thorough detection.
 func IsTruecolorSupported() bool {
-	return os.Getenv("COLORTERM") == "truecolor"
+	switch os.Getenv("COLORTERM") {
+	case "truecolor", "24bit":
+		return true
+	default:
+		return false
+	}
+}
+
+// IsUTF8Supported returns whether the environment this process runs in supports UTF-8 output. This
+// function inspects the locale defined by the environment variables LC_ALL, LC_CTYPE and LANG (in that
+// order). If none of them is set, UTF-8 is assumed to be supported as virtually all terminals in use
+// nowadays support it.
+//
+// If UTF-8 is not supported, a Terminal translates box drawing characters written to it into DEC special
+// graphics (see SetBoxDrawingTranslation).
+func IsUTF8Supported() bool {
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if v := os.Getenv(name); v != "" {
+			v = strings.ToLower(v)
+			return strings.Contains(v, "utf-8") || strings.Contains(v, "utf8")
+		}
+	}
+
+	return true
+}
+
+// IsExtendedUnderlineSupported returns whether the environment this process runs in supports extended
+// underline styles (such as curly or dotted underlines) and underline colors. Terminals supporting them
+// advertise the Smulx capability in their terminfo entry. This function looks up the compiled terminfo
+// entry for TERM (searching TERMINFO, ~/.terminfo, TERMINFO_DIRS and the system locations) and reports
+// whether it contains Smulx.
+//
+// Only if no terminfo entry exists for TERM, this function falls back to heuristics based on the
+// environment variables TERM, TERM_PROGRAM and VTE_VERSION to detect terminal emulators known to support
+// extended underlines.
+func IsExtendedUnderlineSupported() bool {
+	term := os.Getenv("TERM")
+
+	if data, ok := readTerminfo(term); ok {
+		caps, err := terminfoExtendedStrings(data)
+		return err == nil && caps["Smulx"]
+	}
+
+	for _, prefix := range []string{"xterm-kitty", "xterm-ghostty", "wezterm", "foot", "alacritty", "contour", "mintty"} {
+		if strings.HasPrefix(term, prefix) {
+			return true
+		}
+	}
+
+	switch os.Getenv("TERM_PROGRAM") {
+	case "WezTerm", "ghostty", "vscode", "mintty":
+		return true
+	}
+
+	// VTE based terminals (such as GNOME Terminal) support extended underlines since version 0.51.2.
+	if v, err := strconv.Atoi(os.Getenv("VTE_VERSION")); err == nil && v >= 5102 {
+		return true
+	}
+
+	return false
 }
 
 // Terminal implements both read and write access to the terminal. By default, it runs on STDIN/STDOUT but can
 // be configured to work with other file descriptors as well.
 type Terminal struct {
 	r, w        *os.File
+	out         *sgr.Writer
+	strip       *csi.StripWriter
+	box         *csi.BoxDrawingWriter
 	inputReader *input.Reader
 
 	rawModeRestoreState *rawmode.State
+
+	synchronizedOutputProbe     sync.Once
+	synchronizedOutputSupported bool
+
+	graphemeClusteringProbe     sync.Once
+	graphemeClusteringSupported bool
 }
 
 // New creates a new Terminal using os.Stdin for input and os.Stdout for output.
@@ -45,6 +118,12 @@ func NewWithFile(r, w *os.File) *Terminal {
 		w:           w,
 		inputReader: &input.Reader{Reader: r},
 	}
+	t.out = sgr.NewWriter(w, DetectColorProfile(t.IsTerminal()))
+	t.strip = csi.NewStripWriter(w)
+	t.out.SetExtendedUnderline(IsExtendedUnderlineSupported())
+	if !IsUTF8Supported() {
+		t.box = csi.NewBoxDrawingWriter(t.out)
+	}
 
 	return &t
 }
@@ -54,6 +133,27 @@ func (t *Terminal) IsTerminal() bool {
 	return rawmode.IsTerminal(t.w.Fd())
 }
 
+// ColorProfile returns the color profile used to render output written to t. The profile is detected using
+// DetectColorProfile when t is created.
+func (t *Terminal) ColorProfile() sgr.ColorProfile {
+	return t.out.Profile()
+}
+
+// SetColorProfile sets the color profile used to render output written to t. Colors contained in SGR
+// sequences written to t are converted to the nearest color supported by p. If p is sgr.ProfileNone and t
+// is not connected to a terminal, SGR sequences are removed.
+func (t *Terminal) SetColorProfile(p sgr.ColorProfile) {
+	t.out.SetProfile(p)
+}
+
+// SetExtendedUnderline enables or disables extended underline styles and underline colors in output
+// written to t. When disabled, extended underline styles are replaced with a single underline and underline
+// colors are removed. By default, extended underlines are enabled if IsExtendedUnderlineSupported reports
+// support.
+func (t *Terminal) SetExtendedUnderline(enabled bool) {
+	t.out.SetExtendedUnderline(enabled)
+}
+
 // EnterRawMode activates the terminal raw mode. In raw mode, key presses are sent down to the FD directly
 // and no line buffering happens (as in canonical mode).
 //
@@ -71,28 +171,158 @@ func (t *Terminal) ExitRawMode() error {
 		return nil
 	}
 
-	return rawmode.Restore(t.r.Fd(), t.rawModeRestoreState)
+	if err := rawmode.Restore(t.r.Fd(), t.rawModeRestoreState); err != nil {
+		return err
+	}
+
+	t.rawModeRestoreState = nil
+	return nil
 }
 
-// Write writes the bytes in buf to the terminal and returns the number of bytes written and any error.
-// Note that an error may occur after buf has been partially written. In that case, the returned number of
-// bytes represents the number of bytes sucessfully written.
-func (t *Terminal) Write(buf []byte) (int, error) {
-	if !t.IsTerminal() {
-		buf = sgr.Remove(buf)
+// Synchronized invokes fn and wraps all output written during the invocation in a synchronized update.
+// A terminal supporting synchronized output (mode 2026) renders all the output at once when fn returns
+// which prevents flickering during full screen redraws. The end of the synchronized update is sent even if
+// fn panics.
+//
+// Support for synchronized output is probed with a DECRQM query the first time Synchronized is called. If
+// the terminal does not support it (or t is not connected to a terminal), fn is invoked without wrapping
+// its output. Synchronized returns the error returned from fn or any error that occurred while writing the
+// control sequences.
+func (t *Terminal) Synchronized(fn func() error) (err error) {
+	if !t.isSynchronizedOutputSupported() {
+		return fn()
 	}
 
-	n, err := t.w.Write(buf)
+	if _, err = t.WriteString(csi.BeginSynchronizedUpdate); err != nil {
+		return
+	}
+
+	defer func() {
+		if _, endErr := t.WriteString(csi.EndSynchronizedUpdate); err == nil {
+			err = endErr
+		}
+	}()
+
+	return fn()
+}
 
-	if err == nil && n < len(buf) {
-		err = fmt.Errorf("partial write: %d < %d", n, len(buf))
+// isSynchronizedOutputSupported probes whether the terminal supports synchronized output. The probe is
+// executed only once and the result is cached.
+func (t *Terminal) isSynchronizedOutputSupported() bool {
+	t.synchronizedOutputProbe.Do(func() {
+		t.synchronizedOutputSupported = t.isPrivateModeSupported(csi.SynchronizedOutputMode)
+	})
+
+	return t.synchronizedOutputSupported
+}
+
+// IsGraphemeClusteringSupported returns whether the terminal supports grapheme clustering (mode 2027).
+// Support is probed with a DECRQM query the first time this method is called; the result is cached. A
+// terminal supporting grapheme clustering can be switched to use the widths of whole grapheme clusters
+// (such as emoji ZWJ sequences) as computed by package text by writing csi.EnableGraphemeClustering.
+func (t *Terminal) IsGraphemeClusteringSupported() bool {
+	t.graphemeClusteringProbe.Do(func() {
+		t.graphemeClusteringSupported = t.isPrivateModeSupported(csi.GraphemeClusteringMode)
+	})
+
+	return t.graphemeClusteringSupported
+}
+
+// ProbeWidth determines the number of cells the terminal uses to display s by comparing the cursor
+// positions before and after writing s (see csi.ProbeWidth). s is erased afterwards. Use this method to
+// check whether the terminal's width calculation matches the one used by package text. The terminal is
+// temporarily switched to raw mode to read the responses, if not already in raw mode.
+func (t *Terminal) ProbeWidth(s string) (width int, err error) {
+	err = t.withRawMode(func() (err error) {
+		width, err = csi.ProbeWidth(t, s)
+		return
+	})
+	return
+}
+
+// isPrivateModeSupported queries the state of the private mode using DECRQM and returns whether the mode
+// is supported. If t is not connected to a terminal, false is returned.
+func (t *Terminal) isPrivateModeSupported(mode int) (supported bool) {
+	if !t.IsTerminal() || !rawmode.IsTerminal(t.r.Fd()) {
+		return false
 	}
 
-	if err != nil {
-		return n, err
+	t.withRawMode(func() error {
+		state, err := csi.GetPrivateModeState(t, mode)
+		supported = err == nil && state.IsSupported()
+		return err
+	})
+
+	return
+}
+
+// withRawMode invokes fn with t in raw mode. If t is not in raw mode, raw mode is entered before and exited
+// after invoking fn.
+func (t *Terminal) withRawMode(fn func() error) error {
+	if t.rawModeRestoreState == nil {
+		if err := t.EnterRawMode(); err != nil {
+			return err
+		}
+		defer t.ExitRawMode()
 	}
 
-	return n, nil
+	return fn()
+}
+
+// SetBoxDrawingTranslation sets whether box drawing characters written to t are translated into DEC special
+// graphics (see csi.TranslateBoxDrawing). Translation is enabled when t is created if IsUTF8Supported
+// reports false. When disabling translation, all bytes held back by the translation are flushed and any
+// error is returned.
+func (t *Terminal) SetBoxDrawingTranslation(enabled bool) error {
+	if enabled {
+		if t.box == nil {
+			t.box = csi.NewBoxDrawingWriter(t.out)
+		}
+		return nil
+	}
+
+	if t.box == nil {
+		return nil
+	}
+
+	err := t.box.Flush()
+	t.box = nil
+	return err
+}
+
+// Write writes the bytes in buf to the terminal and returns the number of bytes written and any error.
+// As buf may be transformed before writing (see below), the number of bytes written is either len(buf) or
+// 0 if an error occured.
+//
+// SGR sequences contained in buf are adjusted to t's color profile: colors are downsampled to the nearest
+// supported color. If t uses sgr.ProfileNone and is not connected to a terminal, all control functions
+// (see csi.Strip) are removed. Control sequences split across multiple calls to Write are handled
+// correctly; an incomplete sequence at the end of buf is held back until the next call to Write or Flush.
+// If box drawing translation is enabled (see SetBoxDrawingTranslation), box drawing characters are
+// translated into DEC special graphics.
+func (t *Terminal) Write(buf []byte) (int, error) {
+	if t.ColorProfile() == sgr.ProfileNone && !t.IsTerminal() {
+		return t.strip.Write(buf)
+	}
+
+	if t.box != nil {
+		return t.box.Write(buf)
+	}
+
+	return t.out.Write(buf)
+}
+
+// Flush writes all bytes held back by Write while waiting for the remainder of a control sequence.
+func (t *Terminal) Flush() error {
+	if t.box != nil {
+		if err := t.box.Flush(); err != nil {
+			return err
+		}
+	}
+	if err := t.out.Flush(); err != nil {
+		return err
+	}
+	return t.strip.Flush()
 }
 
 // Read reads bytes from the terminal until buf is filled. It returns the number of bytes read (which may be
@@ -117,7 +347,7 @@ func (t *Terminal) Size() (w, h int, err error) {
 
 // WriteString writes s to t. This method makes *Terminal satisfy io.StringWriter.
 func (t *Terminal) WriteString(s string) (n int, err error) {
-	return t.w.WriteString(s)
+	return t.Write([]byte(s))
 }
 
 // Print is a convenient shortcut to calling
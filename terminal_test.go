@@ -0,0 +1,134 @@
+package terminal
+
+import (
+	"io"
+	"os"
+	"testing"
+
+	"github.com/halimath/expect"
+	"github.com/halimath/expect/is"
+	"github.com/halimath/terminal/sgr"
+)
+
+func TestIsUTF8Supported(t *testing.T) {
+	type testCase struct {
+		lcAll, lcCtype, lang string
+		want                 bool
+	}
+
+	tests := []testCase{
+		{"", "", "", true},
+		{"", "", "en_US.UTF-8", true},
+		{"", "de_DE.utf8", "C", true},
+		{"C", "", "en_US.UTF-8", false},
+		{"", "", "POSIX", false},
+	}
+
+	for _, test := range tests {
+		t.Setenv("LC_ALL", test.lcAll)
+		t.Setenv("LC_CTYPE", test.lcCtype)
+		t.Setenv("LANG", test.lang)
+
+		expect.WithMessage(t, "%#v", test).
+			That(is.EqualTo(IsUTF8Supported(), test.want))
+	}
+}
+
+func TestIsExtendedUnderlineSupported(t *testing.T) {
+	dir := t.TempDir()
+	writeTerminfo(t, dir, "with-smulx", map[string]string{"Ms": "\x1b]52;%p1%s;%p2%s\a", "Smulx": "\x1b[4:%p1%dm"})
+	writeTerminfo(t, dir, "without-smulx", map[string]string{"Ms": "\x1b]52;%p1%s;%p2%s\a"})
+
+	t.Setenv("HOME", dir)
+	t.Setenv("TERMINFO", dir)
+	t.Setenv("TERMINFO_DIRS", dir)
+
+	type testCase struct {
+		term, termProgram, vteVersion string
+		want                          bool
+	}
+
+	tests := []testCase{
+		// terminfo entry exists
+		{"with-smulx", "", "", true},
+		{"without-smulx", "", "", false},
+		{"without-smulx", "WezTerm", "7600", false},
+
+		// no terminfo entry
+		{"xterm-256color", "", "", false},
+		{"xterm-kitty", "", "", true},
+		{"xterm-256color", "WezTerm", "", true},
+		{"xterm-256color", "", "5002", false},
+		{"xterm-256color", "", "7600", true},
+	}
+
+	for _, test := range tests {
+		t.Setenv("TERM", test.term)
+		t.Setenv("TERM_PROGRAM", test.termProgram)
+		t.Setenv("VTE_VERSION", test.vteVersion)
+
+		expect.WithMessage(t, "%#v", test).
+			That(is.EqualTo(IsExtendedUnderlineSupported(), test.want))
+	}
+}
+
+func TestTerminal_Write(t *testing.T) {
+	type testCase struct {
+		profile sgr.ColorProfile
+		in      string
+		want    string
+	}
+
+	tests := []testCase{
+		{sgr.ProfileTrueColor, "\x1b[38;2;255;0;0mred\x1b[0m", "\x1b[38;2;255;0;0mred\x1b[0m"},
+		{sgr.ProfileANSI256, "\x1b[38;2;255;0;0mred\x1b[0m", "\x1b[38;5;196mred\x1b[0m"},
+		{sgr.ProfileNone, "\x1b[1;38;2;255;0;0mred\x1b[0m", "red"},
+		{sgr.ProfileNone, "\x1b[2J\x1b]0;title\x07\x1b7plain\x1b8 text", "plain text"},
+	}
+
+	for _, test := range tests {
+		r, w, err := os.Pipe()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		term := NewWithFile(r, w)
+		term.SetColorProfile(test.profile)
+
+		n, err := term.WriteString(test.in)
+		w.Close()
+		got, _ := io.ReadAll(r)
+		r.Close()
+
+		expect.That(t,
+			is.NoError(err),
+			is.EqualTo(n, len(test.in)),
+			is.EqualTo(string(got), test.want),
+		)
+	}
+}
+
+func TestTerminal_SetBoxDrawingTranslation(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	term := NewWithFile(r, w)
+	term.SetColorProfile(sgr.ProfileTrueColor)
+	term.SetBoxDrawingTranslation(true)
+
+	// The write ends with the first byte of "ä" which is held back until translation is disabled.
+	_, err = term.WriteString("\x1b[1m┌─┐\x1b[0m\xc3")
+	disableErr := term.SetBoxDrawingTranslation(false)
+	term.WriteString("\xa4└┘")
+	w.Close()
+	got, _ := io.ReadAll(r)
+	r.Close()
+
+	expect.That(t,
+		is.NoError(err),
+		is.NoError(disableErr),
+		is.EqualTo(string(got), "\x1b[1m\x1b(0lqk\x1b(B\x1b[0mä└┘"),
+	)
+}
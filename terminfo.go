@@ -0,0 +1,194 @@
+package terminal
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// terminfoDirs returns the directories to search for compiled terminfo entries in the order used by
+// ncurses: $TERMINFO, ~/.terminfo, $TERMINFO_DIRS and the system default locations.
+func terminfoDirs() []string {
+	var dirs []string
+
+	if d := os.Getenv("TERMINFO"); d != "" {
+		dirs = append(dirs, d)
+	}
+
+	if home, err := os.UserHomeDir(); err == nil {
+		dirs = append(dirs, filepath.Join(home, ".terminfo"))
+	}
+
+	systemDirs := []string{"/etc/terminfo", "/lib/terminfo", "/usr/share/terminfo", "/usr/lib/terminfo", "/usr/share/lib/terminfo"}
+
+	if v, ok := os.LookupEnv("TERMINFO_DIRS"); ok {
+		for _, d := range strings.Split(v, ":") {
+			if d == "" {
+				// An empty entry denotes the system default locations.
+				dirs = append(dirs, systemDirs...)
+			} else {
+				dirs = append(dirs, d)
+			}
+		}
+	} else {
+		dirs = append(dirs, systemDirs...)
+	}
+
+	return dirs
+}
+
+// readTerminfo reads the compiled terminfo entry for term. The returned bool is false if no entry exists.
+func readTerminfo(term string) ([]byte, bool) {
+	if term == "" || strings.ContainsAny(term, "/\\") {
+		return nil, false
+	}
+
+	for _, dir := range terminfoDirs() {
+		// Entries are stored in subdirectories named after the first character of term. Some systems
+		// (i.e. macOS) use the character's hex code instead.
+		for _, sub := range []string{term[:1], fmt.Sprintf("%02x", term[0])} {
+			data, err := os.ReadFile(filepath.Join(dir, sub, term))
+			if err == nil {
+				return data, true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+const (
+	terminfoMagic         = 0o432  // Legacy format using 16 bit numbers
+	terminfoMagicExtended = 0o1036 // Format using 32 bit numbers
+)
+
+var errInvalidTerminfo = errors.New("invalid terminfo entry")
+
+// terminfoExtendedStrings parses the compiled terminfo entry data (see term(5)) and returns the names of
+// all extended (user defined) string capabilities with a value, such as Smulx.
+func terminfoExtendedStrings(data []byte) (map[string]bool, error) {
+	r := terminfoReader{data: data}
+
+	magic := r.short()
+	numSize := 2
+	switch magic {
+	case terminfoMagic:
+	case terminfoMagicExtended:
+		numSize = 4
+	default:
+		return nil, fmt.Errorf("%w: bad magic %o", errInvalidTerminfo, magic)
+	}
+
+	namesSize, boolCount, numCount, strCount, strTableSize := r.short(), r.short(), r.short(), r.short(), r.short()
+
+	// Skip the legacy section.
+	r.skip(namesSize + boolCount)
+	r.align()
+	r.skip(numCount*numSize + strCount*2 + strTableSize)
+	r.align()
+
+	caps := make(map[string]bool)
+	if r.err != nil || r.pos >= len(data) {
+		// No extended section
+		return caps, r.err
+	}
+
+	extBoolCount, extNumCount, extStrCount := r.short(), r.short(), r.short()
+	r.short() // number of items in the string table
+	extStrTableSize := r.short()
+
+	r.skip(extBoolCount)
+	r.align()
+	r.skip(extNumCount * numSize)
+
+	values := make([]int, extStrCount)
+	for i := range values {
+		values[i] = int(int16(r.short()))
+	}
+
+	names := make([]int, extBoolCount+extNumCount+extStrCount)
+	for i := range names {
+		names[i] = r.short()
+	}
+
+	table := r.bytes(extStrTableSize)
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	// The string table contains all string values followed by all names. Name offsets are relative to the
+	// end of the last value.
+	namesStart := 0
+	for _, off := range values {
+		if off < 0 || off >= len(table) {
+			continue
+		}
+		if end := off + bytes.IndexByte(table[off:], 0) + 1; end > namesStart {
+			namesStart = end
+		}
+	}
+
+	for i, off := range values {
+		if off < 0 {
+			// Absent or canceled
+			continue
+		}
+
+		start := namesStart + names[extBoolCount+extNumCount+i]
+		if start >= len(table) {
+			return nil, fmt.Errorf("%w: name offset out of range", errInvalidTerminfo)
+		}
+
+		name := table[start:]
+		if end := bytes.IndexByte(name, 0); end >= 0 {
+			name = name[:end]
+		}
+		caps[string(name)] = true
+	}
+
+	return caps, nil
+}
+
+// terminfoReader reads values from a compiled terminfo entry. Once an error occurred, all further reads
+// return zero values.
+type terminfoReader struct {
+	data []byte
+	pos  int
+	err  error
+}
+
+func (r *terminfoReader) short() int {
+	b := r.bytes(2)
+	if b == nil {
+		return 0
+	}
+	return int(binary.LittleEndian.Uint16(b))
+}
+
+func (r *terminfoReader) bytes(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n < 0 || r.pos+n > len(r.data) {
+		r.err = fmt.Errorf("%w: unexpected end of data", errInvalidTerminfo)
+		return nil
+	}
+	b := r.data[r.pos : r.pos+n]
+	r.pos += n
+	return b
+}
+
+func (r *terminfoReader) skip(n int) {
+	r.bytes(n)
+}
+
+// align skips a padding byte to continue at an even offset.
+func (r *terminfoReader) align() {
+	if r.pos%2 == 1 && r.pos < len(r.data) {
+		r.pos++
+	}
+}
@@ -0,0 +1,115 @@
+package terminal
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/halimath/expect"
+	"github.com/halimath/expect/is"
+)
+
+func TestTerminfoExtendedStrings(t *testing.T) {
+	t.Run("extended", func(t *testing.T) {
+		caps, err := terminfoExtendedStrings(compileTerminfo("test", map[string]string{"Smulx": "\x1b[4:%p1%dm", "Ss": "\x1b[%p1%d q"}))
+		expect.That(t,
+			is.NoError(err),
+			is.DeepEqualTo(caps, map[string]bool{"Smulx": true, "Ss": true}),
+		)
+	})
+
+	t.Run("noExtendedSection", func(t *testing.T) {
+		data := compileTerminfo("test", nil)
+		caps, err := terminfoExtendedStrings(data[:18])
+		expect.That(t,
+			is.NoError(err),
+			is.DeepEqualTo(caps, map[string]bool{}),
+		)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := terminfoExtendedStrings([]byte("caboom"))
+		expect.That(t, is.Error(err, errInvalidTerminfo))
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		data := compileTerminfo("test", map[string]string{"Smulx": "\x1b[4:%p1%dm"})
+		_, err := terminfoExtendedStrings(data[:len(data)-4])
+		expect.That(t, is.Error(err, errInvalidTerminfo))
+	})
+}
+
+// writeTerminfo writes a compiled terminfo entry for term containing the extended string capabilities
+// strs to dir.
+func writeTerminfo(t *testing.T, dir, term string, strs map[string]string) {
+	t.Helper()
+
+	sub := filepath.Join(dir, term[:1])
+	if err := os.MkdirAll(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(sub, term), compileTerminfo(term, strs), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// compileTerminfo creates a compiled terminfo entry (legacy format) named name with no standard
+// capabilities and the extended string capabilities strs.
+func compileTerminfo(name string, strs map[string]string) []byte {
+	var buf bytes.Buffer
+	short := func(v int) { binary.Write(&buf, binary.LittleEndian, int16(v)) }
+	align := func() {
+		if buf.Len()%2 == 1 {
+			buf.WriteByte(0)
+		}
+	}
+
+	short(terminfoMagic)
+	short(len(name) + 1)
+	short(0) // booleans
+	short(0) // numbers
+	short(0) // strings
+	short(0) // string table size
+	buf.WriteString(name)
+	buf.WriteByte(0)
+	align()
+
+	names := make([]string, 0, len(strs))
+	for n := range strs {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	var table bytes.Buffer
+	var valueOffsets, nameOffsets []int
+	for _, n := range names {
+		valueOffsets = append(valueOffsets, table.Len())
+		table.WriteString(strs[n])
+		table.WriteByte(0)
+	}
+	namesStart := table.Len()
+	for _, n := range names {
+		nameOffsets = append(nameOffsets, table.Len()-namesStart)
+		table.WriteString(n)
+		table.WriteByte(0)
+	}
+
+	short(0) // extended booleans
+	short(0) // extended numbers
+	short(len(names))
+	short(2 * len(names))
+	short(table.Len())
+	for _, o := range valueOffsets {
+		short(o)
+	}
+	for _, o := range nameOffsets {
+		short(o)
+	}
+	buf.Write(table.Bytes())
+
+	return buf.Bytes()
+}
@@ -0,0 +1,303 @@
+package text
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// graphemeProperty defines the Grapheme_Cluster_Break property of a rune as defined in Unicode Standard
+// Annex #29.
+type graphemeProperty int
+
+const (
+	gbOther graphemeProperty = iota
+	gbCR
+	gbLF
+	gbControl
+	gbExtend
+	gbZWJ
+	gbRegionalIndicator
+	gbPrepend
+	gbSpacingMark
+	gbL
+	gbV
+	gbT
+	gbLV
+	gbLVT
+	gbExtendedPictographic
+)
+
+// prepend contains the runes with the Grapheme_Cluster_Break property Prepend.
+var prepend = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{0x0600, 0x0605, 1},
+		{0x06dd, 0x06dd, 1},
+		{0x070f, 0x070f, 1},
+		{0x0890, 0x0891, 1},
+		{0x08e2, 0x08e2, 1},
+		{0x0d4e, 0x0d4e, 1},
+	},
+	R32: []unicode.Range32{
+		{0x110bd, 0x110bd, 1},
+		{0x110cd, 0x110cd, 1},
+		{0x111c2, 0x111c3, 1},
+		{0x1193f, 0x1193f, 1},
+		{0x11941, 0x11941, 1},
+		{0x11a3a, 0x11a3a, 1},
+		{0x11a84, 0x11a89, 1},
+		{0x11d46, 0x11d46, 1},
+		{0x11f02, 0x11f02, 1},
+	},
+}
+
+// extendedPictographic contains the runes with the Extended_Pictographic property as defined in Unicode
+// Technical Standard #51.
+var extendedPictographic = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{0x00a9, 0x00a9, 1},
+		{0x00ae, 0x00ae, 1},
+		{0x203c, 0x203c, 1},
+		{0x2049, 0x2049, 1},
+		{0x2122, 0x2122, 1},
+		{0x2139, 0x2139, 1},
+		{0x2194, 0x2199, 1},
+		{0x21a9, 0x21aa, 1},
+		{0x231a, 0x231b, 1},
+		{0x2328, 0x2328, 1},
+		{0x2388, 0x2388, 1},
+		{0x23cf, 0x23cf, 1},
+		{0x23e9, 0x23f3, 1},
+		{0x23f8, 0x23fa, 1},
+		{0x24c2, 0x24c2, 1},
+		{0x25aa, 0x25ab, 1},
+		{0x25b6, 0x25b6, 1},
+		{0x25c0, 0x25c0, 1},
+		{0x25fb, 0x25fe, 1},
+		{0x2600, 0x2605, 1},
+		{0x2607, 0x2612, 1},
+		{0x2614, 0x2685, 1},
+		{0x2690, 0x2705, 1},
+		{0x2708, 0x2712, 1},
+		{0x2714, 0x2714, 1},
+		{0x2716, 0x2716, 1},
+		{0x271d, 0x271d, 1},
+		{0x2721, 0x2721, 1},
+		{0x2728, 0x2728, 1},
+		{0x2733, 0x2734, 1},
+		{0x2744, 0x2744, 1},
+		{0x2747, 0x2747, 1},
+		{0x274c, 0x274c, 1},
+		{0x274e, 0x274e, 1},
+		{0x2753, 0x2755, 1},
+		{0x2757, 0x2757, 1},
+		{0x2763, 0x2767, 1},
+		{0x2795, 0x2797, 1},
+		{0x27a1, 0x27a1, 1},
+		{0x27b0, 0x27b0, 1},
+		{0x27bf, 0x27bf, 1},
+		{0x2934, 0x2935, 1},
+		{0x2b05, 0x2b07, 1},
+		{0x2b1b, 0x2b1c, 1},
+		{0x2b50, 0x2b50, 1},
+		{0x2b55, 0x2b55, 1},
+		{0x3030, 0x3030, 1},
+		{0x303d, 0x303d, 1},
+		{0x3297, 0x3297, 1},
+		{0x3299, 0x3299, 1},
+	},
+	R32: []unicode.Range32{
+		{0x1f000, 0x1f0ff, 1},
+		{0x1f10d, 0x1f10f, 1},
+		{0x1f12f, 0x1f12f, 1},
+		{0x1f16c, 0x1f171, 1},
+		{0x1f17e, 0x1f17f, 1},
+		{0x1f18e, 0x1f18e, 1},
+		{0x1f191, 0x1f19a, 1},
+		{0x1f1ad, 0x1f1e5, 1},
+		{0x1f201, 0x1f20f, 1},
+		{0x1f21a, 0x1f21a, 1},
+		{0x1f22f, 0x1f22f, 1},
+		{0x1f232, 0x1f23a, 1},
+		{0x1f23c, 0x1f23f, 1},
+		{0x1f249, 0x1f3fa, 1},
+		{0x1f400, 0x1f53d, 1},
+		{0x1f546, 0x1f64f, 1},
+		{0x1f680, 0x1f6ff, 1},
+		{0x1f774, 0x1f77f, 1},
+		{0x1f7d5, 0x1f7ff, 1},
+		{0x1f80c, 0x1f80f, 1},
+		{0x1f848, 0x1f84f, 1},
+		{0x1f85a, 0x1f85f, 1},
+		{0x1f888, 0x1f88f, 1},
+		{0x1f8ae, 0x1f8ff, 1},
+		{0x1f90c, 0x1f93a, 1},
+		{0x1f93c, 0x1f945, 1},
+		{0x1f947, 0x1faff, 1},
+		{0x1fc00, 0x1fffd, 1},
+	},
+}
+
+// graphemePropertyOf returns the Grapheme_Cluster_Break property of r.
+func graphemePropertyOf(r rune) graphemeProperty {
+	switch {
+	case r == '\r':
+		return gbCR
+	case r == '\n':
+		return gbLF
+	case r < 0x20 || (r >= 0x7f && r < 0xa0):
+		return gbControl
+	case r < 0xa9:
+		// Fast path for latin characters.
+		return gbOther
+	case r == zeroWidthJoiner:
+		return gbZWJ
+	case r == zeroWidthNonJoiner, isEmojiModifier(r), r >= 0xe0020 && r <= 0xe007f:
+		// Zero width non-joiner, emoji modifiers and tags extend the preceding character.
+		return gbExtend
+	case isRegionalIndicator(r):
+		return gbRegionalIndicator
+	case r >= 0x1100 && r <= 0x115f, r >= 0xa960 && r <= 0xa97c:
+		return gbL
+	case r >= 0x1160 && r <= 0x11a7, r >= 0xd7b0 && r <= 0xd7c6:
+		return gbV
+	case r >= 0x11a8 && r <= 0x11ff, r >= 0xd7cb && r <= 0xd7fb:
+		return gbT
+	case r >= 0xac00 && r <= 0xd7a3:
+		if (r-0xac00)%28 == 0 {
+			return gbLV
+		}
+		return gbLVT
+	case unicode.Is(prepend, r):
+		return gbPrepend
+	case unicode.In(r, unicode.Mn, unicode.Me):
+		return gbExtend
+	case unicode.Is(unicode.Mc, r):
+		return gbSpacingMark
+	case unicode.In(r, unicode.Zl, unicode.Zp, unicode.Cf), r == 0xfeff:
+		return gbControl
+	case unicode.Is(extendedPictographic, r):
+		return gbExtendedPictographic
+	}
+
+	return gbOther
+}
+
+// FirstGrapheme returns the first grapheme cluster (user perceived character) contained in s and the
+// remainder of s. Grapheme cluster boundaries are determined according to the extended grapheme cluster
+// rules defined in Unicode Standard Annex #29 (without the Indic conjunct rule GB9c). Invalid UTF-8
+// sequences form clusters of a single byte.
+func FirstGrapheme(s string) (grapheme, rest string) {
+	if len(s) == 0 {
+		return "", ""
+	}
+
+	r, n := utf8.DecodeRuneInString(s)
+	prev := graphemePropertyOf(r)
+	if r == utf8.RuneError && n <= 1 {
+		return s[:n], s[n:]
+	}
+
+	// State for GB11 (emoji ZWJ sequences) and GB12/GB13 (regional indicator pairs)
+	pictographic := prev == gbExtendedPictographic
+	regionalIndicators := 0
+	if prev == gbRegionalIndicator {
+		regionalIndicators = 1
+	}
+
+	for n < len(s) {
+		r, size := utf8.DecodeRuneInString(s[n:])
+		if r == utf8.RuneError && size <= 1 {
+			break
+		}
+		next := graphemePropertyOf(r)
+
+		if isGraphemeBoundary(prev, next, pictographic, regionalIndicators) {
+			break
+		}
+
+		switch {
+		case next == gbRegionalIndicator:
+			regionalIndicators++
+		case next == gbExtendedPictographic:
+			pictographic = true
+		case next != gbExtend && next != gbZWJ:
+			pictographic = false
+		}
+
+		prev = next
+		n += size
+	}
+
+	return s[:n], s[n:]
+}
+
+// isGraphemeBoundary returns whether there is a grapheme cluster boundary between two runes with the
+// properties prev and next. pictographic reports whether the current cluster contains an extended
+// pictographic optionally followed by extending characters; regionalIndicators is the number of regional
+// indicators in the current cluster.
+func isGraphemeBoundary(prev, next graphemeProperty, pictographic bool, regionalIndicators int) bool {
+	switch {
+	case prev == gbCR && next == gbLF: // GB3
+		return false
+	case prev == gbCR, prev == gbLF, prev == gbControl: // GB4
+		return true
+	case next == gbCR, next == gbLF, next == gbControl: // GB5
+		return true
+	case prev == gbL && (next == gbL || next == gbV || next == gbLV || next == gbLVT): // GB6
+		return false
+	case (prev == gbLV || prev == gbV) && (next == gbV || next == gbT): // GB7
+		return false
+	case (prev == gbLVT || prev == gbT) && next == gbT: // GB8
+		return false
+	case next == gbExtend, next == gbZWJ: // GB9
+		return false
+	case next == gbSpacingMark: // GB9a
+		return false
+	case prev == gbPrepend: // GB9b
+		return false
+	case prev == gbZWJ && next == gbExtendedPictographic && pictographic: // GB11
+		return false
+	case prev == gbRegionalIndicator && next == gbRegionalIndicator && regionalIndicators%2 == 1: // GB12, GB13
+		return false
+	}
+
+	return true // GB999
+}
+
+// Graphemes splits s into grapheme clusters (user perceived characters). See FirstGrapheme for details.
+func Graphemes(s string) []string {
+	var graphemes []string
+	for len(s) > 0 {
+		var g string
+		g, s = FirstGrapheme(s)
+		graphemes = append(graphemes, g)
+	}
+	return graphemes
+}
+
+// GraphemeWidth returns the number of cells used to display the grapheme cluster g in a monospaced
+// terminal. The width is determined by the first character of g that is not zero width. An emoji
+// presentation selector (U+FE0F) or a pair of regional indicators (a flag) widens the cluster to two
+// cells.
+func GraphemeWidth(g string) int {
+	width := 0
+	regionalIndicators := 0
+
+	for _, r := range g {
+		switch {
+		case r == variationSelector16:
+			width = 2
+		case isRegionalIndicator(r):
+			regionalIndicators++
+			if regionalIndicators == 2 {
+				width = 2
+			}
+		}
+
+		if width == 0 {
+			width = RuneWidth(r)
+		}
+	}
+
+	return width
+}
@@ -0,0 +1,57 @@
+package text
+
+import (
+	"testing"
+
+	"github.com/halimath/expect"
+	"github.com/halimath/expect/is"
+)
+
+func TestGraphemes(t *testing.T) {
+	type testCase struct {
+		in   string
+		want []string
+	}
+
+	tests := []testCase{
+		{"", nil},
+		{"abc", []string{"a", "b", "c"}},
+		{"\r\n\n", []string{"\r\n", "\n"}},
+		{"cafe\u0301", []string{"c", "a", "f", "e\u0301"}},
+		{"\U0001f469\u200d\U0001f4bbx", []string{"\U0001f469\u200d\U0001f4bb", "x"}},
+		{"\U0001f44d\U0001f3fd!", []string{"\U0001f44d\U0001f3fd", "!"}},
+		{"\U0001f468\u200d\U0001f469\u200d\U0001f467", []string{"\U0001f468\u200d\U0001f469\u200d\U0001f467"}},
+		{"\U0001f1e9\U0001f1ea\U0001f1eb\U0001f1f7\U0001f1e9", []string{"\U0001f1e9\U0001f1ea", "\U0001f1eb\U0001f1f7", "\U0001f1e9"}},
+		{"\u2764\ufe0f", []string{"\u2764\ufe0f"}},
+		{"\u1100\u1161\u11a8\uac00", []string{"\u1100\u1161\u11a8", "\uac00"}},
+		{"a\u200db", []string{"a\u200d", "b"}},
+		{"\u0600a", []string{"\u0600a"}},
+		{"\u0915\u093f", []string{"\u0915\u093f"}},
+		{"a\x1b[1m", []string{"a", "\x1b", "[", "1", "m"}},
+		{"\xffa", []string{"\xff", "a"}},
+	}
+
+	for _, test := range tests {
+		expect.WithMessage(t, "%q", test.in).That(is.DeepEqualTo(Graphemes(test.in), test.want))
+	}
+}
+
+func TestGraphemeWidth(t *testing.T) {
+	tests := map[string]int{
+		"a":                          1,
+		"e\u0301":                    1,
+		"\u65e5":                     2,
+		"\U0001f469\u200d\U0001f4bb": 2,
+		"\U0001f44d\U0001f3fd":       2,
+		"\U0001f1e9\U0001f1ea":       2,
+		"\U0001f1e9":                 1,
+		"\u2764\ufe0f":               2,
+		"\u1100\u1161\u11a8":         2,
+		"\r\n":                       0,
+		"\u0301":                     0,
+	}
+
+	for in, want := range tests {
+		expect.WithMessage(t, "%q", in).That(is.EqualTo(GraphemeWidth(in), want))
+	}
+}
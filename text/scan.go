@@ -0,0 +1,98 @@
+package text
+
+import (
+	"strings"
+
+	"github.com/halimath/terminal/csi"
+	"github.com/halimath/terminal/sgr"
+)
+
+// escapeLength returns the length in bytes of the escape sequence at the beginning of s or 0 if s does not
+// start with an escape sequence. Unterminated sequences extend to the end of s.
+func escapeLength(s string) int {
+	if len(s) == 0 || s[0] != '\x1b' {
+		return 0
+	}
+
+	if len(s) == 1 {
+		return 1
+	}
+
+	switch s[1] {
+	case '[':
+		// Control sequence: parameter and intermediate bytes followed by a final byte.
+		for i := 2; i < len(s); i++ {
+			if s[i] >= 0x40 && s[i] <= 0x7e {
+				return i + 1
+			}
+			if s[i] < 0x20 || s[i] > 0x3f {
+				return i
+			}
+		}
+		return len(s)
+
+	case ']', 'P', 'X', '^', '_':
+		// Control strings (OSC, DCS, SOS, PM, APC) terminated by ST or BEL.
+		for i := 2; i < len(s); i++ {
+			if s[i] == '\a' {
+				return i + 1
+			}
+			if s[i] == '\x1b' && i+1 < len(s) && s[i+1] == '\\' {
+				return i + 2
+			}
+		}
+		return len(s)
+	}
+
+	// Escape sequence: intermediate bytes followed by a final byte.
+	i := 1
+	for i < len(s) && s[i] >= 0x20 && s[i] <= 0x2f {
+		i++
+	}
+	if i < len(s) && s[i] >= 0x30 && s[i] <= 0x7e {
+		return i + 1
+	}
+	return i
+}
+
+// nextCluster returns the length in bytes and the display width of the grapheme cluster at the beginning
+// of s.
+func nextCluster(s string) (n, width int) {
+	g, _ := FirstGrapheme(s)
+	return len(g), GraphemeWidth(g)
+}
+
+// updateStyle updates style with the escape sequence seq if seq is an SGR and returns the resulting style.
+// Any other sequence leaves style unchanged.
+func updateStyle(style sgr.Style, seq string) sgr.Style {
+	if len(seq) < 3 || seq[1] != '[' || seq[len(seq)-1] != 'm' {
+		return style
+	}
+
+	params := seq[2 : len(seq)-1]
+	for i := 0; i < len(params); i++ {
+		if (params[i] < '0' || params[i] > '9') && params[i] != ';' && params[i] != ':' {
+			// Private sequences (such as XTMODKEYS) also use m as their final byte.
+			return style
+		}
+	}
+
+	style, _ = style.Update(sgr.SGR(params))
+	return style
+}
+
+// hyperlinkState returns whether the escape sequence seq opens (true) or closes (false) a hyperlink. ok is
+// false if seq is not an OSC 8 sequence.
+func hyperlinkState(seq string) (open bool, ok bool) {
+	if !strings.HasPrefix(seq, csi.OSC+"8;") {
+		return false, false
+	}
+
+	body := strings.TrimSuffix(strings.TrimSuffix(seq[len(csi.OSC)+2:], csi.StringTerminator), "\a")
+	idx := strings.IndexByte(body, ';')
+	if idx < 0 {
+		return false, false
+	}
+
+	return body[idx+1:] != "", true
+}
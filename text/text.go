@@ -0,0 +1,110 @@
+// Package text provides functions to layout text containing escape sequences for display in a terminal.
+// All functions operate on display widths measured in terminal cells rather than bytes or runes: escape
+// sequences (such as SGRs or hyperlinks) do not occupy any cells, East Asian wide characters and emoji
+// occupy two cells and combining marks are displayed as part of the preceding character.
+//
+// Functions that split or shorten text preserve the contained escape sequences and keep the rendition
+// intact, i.e. Wrap closes active styles at the end of each line and reopens them on the following line.
+package text
+
+import (
+	"strings"
+
+	"github.com/halimath/terminal/csi"
+	"github.com/halimath/terminal/sgr"
+)
+
+// Width returns the number of cells used to display s in a monospaced terminal. Escape sequences and
+// control characters do not contribute to the width. s is expected to contain a single line.
+func Width(s string) int {
+	w := 0
+	for i := 0; i < len(s); {
+		if n := escapeLength(s[i:]); n > 0 {
+			i += n
+			continue
+		}
+
+		n, cw := nextCluster(s[i:])
+		w += cw
+		i += n
+	}
+
+	return w
+}
+
+// Truncate shortens s to be displayed using at most width cells. If s needs to be shortened, tail (such
+// as "…") is appended to the shortened s so that the result still fits into width. All escape sequences up
+// to the cut are preserved and the style active at the cut as well as an open hyperlink are closed after
+// tail. If width is less than 1 and s is not empty, the empty string is returned.
+func Truncate(s string, width int, tail string) string {
+	if Width(s) <= width {
+		return s
+	}
+
+	if width <= 0 {
+		return ""
+	}
+
+	tailWidth := Width(tail)
+	if tailWidth > width {
+		tail = Truncate(tail, width, "")
+		tailWidth = Width(tail)
+	}
+	limit := width - tailWidth
+
+	var b strings.Builder
+	var style sgr.Style
+	hyperlink := false
+
+	w := 0
+	for i := 0; i < len(s); {
+		if n := escapeLength(s[i:]); n > 0 {
+			seq := s[i : i+n]
+			b.WriteString(seq)
+			style = updateStyle(style, seq)
+			if open, ok := hyperlinkState(seq); ok {
+				hyperlink = open
+			}
+			i += n
+			continue
+		}
+
+		n, cw := nextCluster(s[i:])
+		if w+cw > limit {
+			break
+		}
+
+		b.WriteString(s[i : i+n])
+		w += cw
+		i += n
+	}
+
+	b.WriteString(tail)
+
+	if hyperlink {
+		b.WriteString(csi.HyperlinkClose)
+	}
+	if style != (sgr.Style{}) {
+		b.WriteString(sgr.Style{}.Transition(style))
+	}
+
+	return b.String()
+}
+
+// Pad appends spaces to s so that s is displayed using width cells. If s is already wider than width, s
+// is returned unchanged.
+func Pad(s string, width int) string {
+	if w := Width(s); w < width {
+		return s + strings.Repeat(" ", width-w)
+	}
+	return s
+}
+
+// PadLeft prepends spaces to s so that s is displayed using width cells and appears right aligned. If s is
+// already wider than width, s is returned unchanged.
+func PadLeft(s string, width int) string {
+	if w := Width(s); w < width {
+		return strings.Repeat(" ", width-w) + s
+	}
+	return s
+}
@@ -0,0 +1,75 @@
+package text
+
+import (
+	"testing"
+
+	"github.com/halimath/expect"
+	"github.com/halimath/expect/is"
+	"github.com/halimath/terminal/csi"
+	"github.com/halimath/terminal/sgr"
+)
+
+func TestWidth(t *testing.T) {
+	tests := map[string]int{
+		"":                      0,
+		"hello":                 5,
+		sgr.Bold.Apply("hello"): 5,
+		csi.Hyperlink("https://example.com", "link", ""): 4,
+		"\x1b]2;title\x07abc":                            3,
+		"\x1b(0q\x1b(B":                                  1,
+		"\u65e5\u672c\u8a9e":                             6,
+		"caf\u00e9":                                      4,
+		"cafe\u0301":                                     4,
+		"\U0001f600":                                     2,
+		"\u2764\ufe0f":                                   2,
+		"\u2764":                                         1,
+		"\U0001f469\u200d\U0001f4bb":                     2,
+		"\U0001f44d\U0001f3fd":                           2,
+		"\U0001f1e9\U0001f1ea":                           2,
+		"\U0001f1e9\U0001f1ea\U0001f1eb\U0001f1f7": 4,
+		"\u1100\u1161": 2,
+		"a\tb":         2,
+	}
+
+	for in, want := range tests {
+		expect.WithMessage(t, "%q", in).That(is.EqualTo(Width(in), want))
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	type testCase struct {
+		in    string
+		width int
+		tail  string
+		want  string
+	}
+
+	tests := []testCase{
+		{"hello", 5, "…", "hello"},
+		{"hello, world", 5, "…", "hell…"},
+		{"hello, world", 5, "", "hello"},
+		{"日本語テキスト", 5, "…", "日本…"},
+		{"\x1b[31mhello\x1b[0m, world", 4, "…", "\x1b[31mhel…\x1b[39m"},
+		{"\x1b[1mhi\x1b[0m there", 4, ".", "\x1b[1mhi\x1b[0m ."},
+		{csi.Hyperlink("https://example.com", "example", ""), 4, "…", csi.HyperlinkOpen("https://example.com", "") + "exa…" + csi.HyperlinkClose},
+		{"hello", 2, "...", ".."},
+		{"hello", 0, "…", ""},
+		{"hello", -1, "…", ""},
+	}
+
+	for _, test := range tests {
+		expect.WithMessage(t, "%#v", test).
+			That(is.EqualTo(Truncate(test.in, test.width, test.tail), test.want))
+	}
+}
+
+func TestPad(t *testing.T) {
+	expect.That(t,
+		is.EqualTo(Pad("ab", 4), "ab  "),
+		is.EqualTo(Pad("日本", 5), "日本 "),
+		is.EqualTo(Pad(sgr.Bold.Apply("ab"), 3), sgr.Bold.Apply("ab")+" "),
+		is.EqualTo(Pad("abcde", 3), "abcde"),
+		is.EqualTo(PadLeft("ab", 4), "  ab"),
+		is.EqualTo(PadLeft("abcde", 3), "abcde"),
+	)
+}
@@ -0,0 +1,165 @@
+package text
+
+import "unicode"
+
+// wide contains the code points displayed using two cells. It covers the East Asian Wide (W) and
+// Fullwidth (F) characters as defined in Unicode Standard Annex #11 as well as the emoji with default
+// emoji presentation.
+var wide = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{0x1100, 0x115f, 1},
+		{0x231a, 0x231b, 1},
+		{0x2329, 0x232a, 1},
+		{0x23e9, 0x23ec, 1},
+		{0x23f0, 0x23f0, 1},
+		{0x23f3, 0x23f3, 1},
+		{0x25fd, 0x25fe, 1},
+		{0x2614, 0x2615, 1},
+		{0x2648, 0x2653, 1},
+		{0x267f, 0x267f, 1},
+		{0x2693, 0x2693, 1},
+		{0x26a1, 0x26a1, 1},
+		{0x26aa, 0x26ab, 1},
+		{0x26bd, 0x26be, 1},
+		{0x26c4, 0x26c5, 1},
+		{0x26ce, 0x26ce, 1},
+		{0x26d4, 0x26d4, 1},
+		{0x26ea, 0x26ea, 1},
+		{0x26f2, 0x26f3, 1},
+		{0x26f5, 0x26f5, 1},
+		{0x26fa, 0x26fa, 1},
+		{0x26fd, 0x26fd, 1},
+		{0x2705, 0x2705, 1},
+		{0x270a, 0x270b, 1},
+		{0x2728, 0x2728, 1},
+		{0x274c, 0x274c, 1},
+		{0x274e, 0x274e, 1},
+		{0x2753, 0x2755, 1},
+		{0x2757, 0x2757, 1},
+		{0x2795, 0x2797, 1},
+		{0x27b0, 0x27b0, 1},
+		{0x27bf, 0x27bf, 1},
+		{0x2b1b, 0x2b1c, 1},
+		{0x2b50, 0x2b50, 1},
+		{0x2b55, 0x2b55, 1},
+		{0x2e80, 0x303e, 1},
+		{0x3041, 0x33ff, 1},
+		{0x3400, 0x4dbf, 1},
+		{0x4e00, 0x9fff, 1},
+		{0xa000, 0xa4cf, 1},
+		{0xa960, 0xa97f, 1},
+		{0xac00, 0xd7a3, 1},
+		{0xf900, 0xfaff, 1},
+		{0xfe10, 0xfe19, 1},
+		{0xfe30, 0xfe6f, 1},
+		{0xff00, 0xff60, 1},
+		{0xffe0, 0xffe6, 1},
+	},
+	R32: []unicode.Range32{
+		{0x16fe0, 0x16fe4, 1},
+		{0x17000, 0x18cff, 1},
+		{0x1b000, 0x1b2ff, 1},
+		{0x1f004, 0x1f004, 1},
+		{0x1f0cf, 0x1f0cf, 1},
+		{0x1f18e, 0x1f18e, 1},
+		{0x1f191, 0x1f19a, 1},
+		{0x1f200, 0x1f202, 1},
+		{0x1f210, 0x1f23b, 1},
+		{0x1f240, 0x1f248, 1},
+		{0x1f250, 0x1f251, 1},
+		{0x1f260, 0x1f265, 1},
+		{0x1f300, 0x1f320, 1},
+		{0x1f32d, 0x1f335, 1},
+		{0x1f337, 0x1f37c, 1},
+		{0x1f37e, 0x1f393, 1},
+		{0x1f3a0, 0x1f3ca, 1},
+		{0x1f3cf, 0x1f3d3, 1},
+		{0x1f3e0, 0x1f3f0, 1},
+		{0x1f3f4, 0x1f3f4, 1},
+		{0x1f3f8, 0x1f43e, 1},
+		{0x1f440, 0x1f440, 1},
+		{0x1f442, 0x1f4fc, 1},
+		{0x1f4ff, 0x1f53d, 1},
+		{0x1f54b, 0x1f54e, 1},
+		{0x1f550, 0x1f567, 1},
+		{0x1f57a, 0x1f57a, 1},
+		{0x1f595, 0x1f596, 1},
+		{0x1f5a4, 0x1f5a4, 1},
+		{0x1f5fb, 0x1f64f, 1},
+		{0x1f680, 0x1f6c5, 1},
+		{0x1f6cc, 0x1f6cc, 1},
+		{0x1f6d0, 0x1f6d2, 1},
+		{0x1f6d5, 0x1f6d7, 1},
+		{0x1f6dc, 0x1f6df, 1},
+		{0x1f6eb, 0x1f6ec, 1},
+		{0x1f6f4, 0x1f6fc, 1},
+		{0x1f7e0, 0x1f7eb, 1},
+		{0x1f7f0, 0x1f7f0, 1},
+		{0x1f90c, 0x1f93a, 1},
+		{0x1f93c, 0x1f945, 1},
+		{0x1f947, 0x1f9ff, 1},
+		{0x1fa70, 0x1faff, 1},
+		{0x20000, 0x2fffd, 1},
+		{0x30000, 0x3fffd, 1},
+	},
+}
+
+const (
+	zeroWidthJoiner      = '\u200d'
+	zeroWidthNonJoiner   = '\u200c'
+	variationSelector15  = '\ufe0e' // text presentation
+	variationSelector16  = '\ufe0f' // emoji presentation
+	regionalIndicatorA   = '\U0001f1e6'
+	regionalIndicatorZ   = '\U0001f1ff'
+	emojiModifierFirst   = '\U0001f3fb'
+	emojiModifierLast    = '\U0001f3ff'
+	hangulJungseongFirst = '\u1160'
+	hangulJongseongLast  = '\u11ff'
+)
+
+// RuneWidth returns the number of cells used to display r in a monospaced terminal. Control characters,
+// combining marks and other zero width characters (such as the zero width joiner) have a width of 0. East
+// Asian wide and fullwidth characters as well as emoji with default emoji presentation have a width of 2.
+// All other runes have a width of 1.
+//
+// Note that the width of a sequence of runes forming a single user perceived character (such as an emoji
+// ZWJ sequence or a flag) is not the sum of the rune widths. Use Width to calculate the width of strings.
+func RuneWidth(r rune) int {
+	switch {
+	case r == 0:
+		return 0
+	case r < 0x20 || (r >= 0x7f && r < 0xa0):
+		return 0
+	case r < 0x300:
+		// Fast path for latin characters.
+		return 1
+	case isZeroWidth(r):
+		return 0
+	case unicode.Is(wide, r):
+		return 2
+	}
+
+	return 1
+}
+
+// isZeroWidth returns whether r is a zero width character, such as a combining mark, a format character
+// or a variation selector.
+func isZeroWidth(r rune) bool {
+	if r >= hangulJungseongFirst && r <= hangulJongseongLast {
+		// Hangul vowels and trailing consonants combine with a preceding leading consonant.
+		return true
+	}
+
+	return unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf)
+}
+
+// isRegionalIndicator returns whether r is a regional indicator symbol. Pairs of regional indicators
+// form flag emoji.
+func isRegionalIndicator(r rune) bool {
+	return r >= regionalIndicatorA && r <= regionalIndicatorZ
+}
+
+// isEmojiModifier returns whether r is an emoji skin tone modifier.
+func isEmojiModifier(r rune) bool {
+	return r >= emojiModifierFirst && r <= emojiModifierLast
+}
@@ -0,0 +1,175 @@
+package text
+
+import (
+	"strings"
+
+	"github.com/halimath/terminal/csi"
+	"github.com/halimath/terminal/sgr"
+)
+
+// Wrap wraps s into lines displayed using at most width cells. Lines are broken at spaces (word wrap);
+// words wider than width are broken at character boundaries (hard wrap). Spaces at the position of a line
+// break are removed while line breaks contained in s are kept.
+//
+// Escape sequences contained in s are preserved. Any style active at the end of a line is reset and an open
+// hyperlink is closed before the line break; both are activated again at the beginning of the next line so
+// each line can be displayed independently (i.e. next to other content). If width is less than 1, s is returned unchanged.
+func Wrap(s string, width int) string {
+	if width < 1 {
+		return s
+	}
+
+	w := wrapper{width: width}
+
+	for i, line := range strings.Split(s, "\n") {
+		if i > 0 {
+			w.lineBreak()
+		}
+		w.wrapLine(line)
+	}
+
+	return w.b.String()
+}
+
+// wrapper implements the state of wrapping a text.
+type wrapper struct {
+	width     int
+	b         strings.Builder
+	style     sgr.Style // style active at the current position of b
+	hyperlink string    // sequence that opened the hyperlink active at the current position of b
+	lineWidth int       // width of the current line
+}
+
+// wrapLine wraps a single line of text which must not contain line breaks.
+func (w *wrapper) wrapLine(line string) {
+	var spaces string
+
+	for len(line) > 0 {
+		if line[0] == ' ' {
+			n := len(line) - len(strings.TrimLeft(line, " "))
+			spaces += line[:n]
+			line = line[n:]
+			continue
+		}
+
+		n := wordLength(line)
+		word := line[:n]
+		line = line[n:]
+
+		wordWidth := Width(word)
+		if wordWidth == 0 {
+			// Escape sequences separated from words by spaces never cause a line break. Keep pending spaces
+			// for the next word.
+			w.writeWord(word)
+			continue
+		}
+		if w.lineWidth > 0 && w.lineWidth+len(spaces)+wordWidth > w.width {
+			w.lineBreak()
+		} else {
+			w.b.WriteString(spaces)
+			w.lineWidth += len(spaces)
+		}
+		spaces = ""
+
+		if w.lineWidth+wordWidth <= w.width {
+			w.writeWord(word)
+			w.lineWidth += wordWidth
+		} else {
+			w.hardWrap(word)
+		}
+	}
+
+	if w.lineWidth+len(spaces) <= w.width {
+		w.b.WriteString(spaces)
+		w.lineWidth += len(spaces)
+	}
+}
+
+// wordLength returns the length in bytes of the word at the beginning of s. A word ends at the first space
+// outside of an escape sequence; escape sequences are always kept as a whole (i.e. a title set via OSC
+// may contain spaces).
+func wordLength(s string) int {
+	i := 0
+	for i < len(s) && s[i] != ' ' {
+		if n := escapeLength(s[i:]); n > 0 {
+			i += n
+			continue
+		}
+
+		n, _ := nextCluster(s[i:])
+		i += n
+	}
+	return i
+}
+
+// writeWord writes word to the current line tracking the style.
+func (w *wrapper) writeWord(word string) {
+	for i := 0; i < len(word); {
+		if n := escapeLength(word[i:]); n > 0 {
+			w.trackEscape(word[i : i+n])
+			i += n
+			continue
+		}
+
+		n, _ := nextCluster(word[i:])
+		i += n
+	}
+
+	w.b.WriteString(word)
+}
+
+// hardWrap writes word breaking it into multiple lines at character boundaries.
+func (w *wrapper) hardWrap(word string) {
+	for i := 0; i < len(word); {
+		if n := escapeLength(word[i:]); n > 0 {
+			seq := word[i : i+n]
+			w.trackEscape(seq)
+			w.b.WriteString(seq)
+			i += n
+			continue
+		}
+
+		n, cw := nextCluster(word[i:])
+		if w.lineWidth > 0 && w.lineWidth+cw > w.width {
+			w.lineBreak()
+		}
+
+		w.b.WriteString(word[i : i+n])
+		w.lineWidth += cw
+		i += n
+	}
+}
+
+// trackEscape updates the active style and hyperlink with the escape sequence seq.
+func (w *wrapper) trackEscape(seq string) {
+	w.style = updateStyle(w.style, seq)
+	if open, ok := hyperlinkState(seq); ok {
+		if open {
+			w.hyperlink = seq
+		} else {
+			w.hyperlink = ""
+		}
+	}
+}
+
+// lineBreak starts a new line. The active style and hyperlink are closed before and activated again after
+// the line break.
+func (w *wrapper) lineBreak() {
+	if w.hyperlink != "" {
+		w.b.WriteString(csi.HyperlinkClose)
+	}
+	if w.style != (sgr.Style{}) {
+		w.b.WriteString(sgr.Style{}.Transition(w.style))
+	}
+
+	w.b.WriteByte('\n')
+
+	if w.style != (sgr.Style{}) {
+		w.b.WriteString(w.style.Transition(sgr.Style{}))
+	}
+	if w.hyperlink != "" {
+		w.b.WriteString(w.hyperlink)
+	}
+
+	w.lineWidth = 0
+}
@@ -0,0 +1,42 @@
+package text
+
+import (
+	"testing"
+
+	"github.com/halimath/expect"
+	"github.com/halimath/expect/is"
+)
+
+func TestWrap(t *testing.T) {
+	type testCase struct {
+		in    string
+		width int
+		want  string
+	}
+
+	tests := []testCase{
+		{"hello world", 20, "hello world"},
+		{"hello world", 5, "hello\nworld"},
+		{"hello world", 8, "hello\nworld"},
+		{"hello   world foo", 11, "hello\nworld foo"},
+		{"  indented text", 10, "  indented\ntext"},
+		{"abcdefghij", 4, "abcd\nefgh\nij"},
+		{"ab abcdefghij", 4, "ab\nabcd\nefgh\nij"},
+		{"first line\nsecond", 6, "first\nline\nsecond"},
+		{"日本語のテキスト", 6, "日本語\nのテキ\nスト"},
+		{"\x1b[31mhello world\x1b[0m", 5, "\x1b[31mhello\x1b[39m\n\x1b[31mworld\x1b[0m"},
+		{"\x1b[1;4mab cd\x1b[0m ef", 2, "\x1b[1;4mab\x1b[0m\n\x1b[1;4mcd\x1b[0m\nef"},
+		{"hello world", 0, "hello world"},
+		{"\x1b]2;my window title\ahello world", 5, "\x1b]2;my window title\ahello\nworld"},
+		{"ab \x1b]2;my title\x1b\\cd", 4, "ab\n\x1b]2;my title\x1b\\cd"},
+		{"\x1b[2 qhello world", 5, "\x1b[2 qhello\nworld"},
+		{"hello \x1b[2 q world", 5, "hello\x1b[2 q\nworld"},
+		{"\x1b]8;;u\x1b\\hello world\x1b]8;;\x1b\\", 5, "\x1b]8;;u\x1b\\hello\x1b]8;;\x1b\\\n\x1b]8;;u\x1b\\world\x1b]8;;\x1b\\"},
+		{"\x1b[1m\x1b]8;id=1;u\ahello world\x1b]8;;\a done", 5, "\x1b[1m\x1b]8;id=1;u\ahello\x1b]8;;\x1b\\\x1b[22m\n\x1b[1m\x1b]8;id=1;u\aworld\x1b]8;;\a\x1b[22m\n\x1b[1mdone"},
+	}
+
+	for _, test := range tests {
+		expect.WithMessage(t, "%#v", test).
+			That(is.EqualTo(Wrap(test.in, test.width), test.want))
+	}
+}